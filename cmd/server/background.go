@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/events"
+	"github.com/miladev95/golang-project-structure/internal/jobs"
+	"github.com/miladev95/golang-project-structure/internal/messaging"
+	"github.com/miladev95/golang-project-structure/internal/outbox"
+	"github.com/miladev95/golang-project-structure/internal/scheduler"
+)
+
+// background is a long-running process that stops when its context ends
+type background struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// backgroundProcesses resolves the background processes enabled in cfg:
+// job workers when withJobs is set, scheduled tasks, the outbox relay and
+// message consumers
+func backgroundProcesses(cfg *config.Config, container *di.Container, withJobs bool) ([]background, error) {
+	var processes []background
+
+	if withJobs {
+		worker, err := di.Resolve[*jobs.Worker](container)
+		if err != nil {
+			return nil, err
+		}
+		processes = append(processes, background{"job workers", worker.Run})
+	}
+
+	if cfg.Scheduler.Enabled {
+		taskScheduler, err := di.Resolve[*scheduler.Scheduler](container)
+		if err != nil {
+			return nil, err
+		}
+		processes = append(processes, background{"scheduler", taskScheduler.Run})
+	}
+
+	if cfg.Outbox.RelayEnabled {
+		relay, err := di.Resolve[*outbox.Relay](container)
+		if err != nil {
+			return nil, err
+		}
+		processes = append(processes, background{"outbox relay", relay.Run})
+	}
+
+	consumers, err := di.Resolve[*messaging.Consumers](container)
+	if err != nil {
+		return nil, err
+	}
+	if consumers.Len() > 0 {
+		processes = append(processes, background{"message consumers", consumers.Run})
+	}
+
+	return processes, nil
+}
+
+// runBackground runs the processes until ctx is cancelled or one of them
+// fails, which stops the others. It returns the first error.
+func runBackground(ctx context.Context, processes []background) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, len(processes))
+	for _, p := range processes {
+		go func(p background) {
+			err := p.run(ctx)
+			if err != nil {
+				err = fmt.Errorf("%s: %w", p.name, err)
+				cancel()
+			}
+			errs <- err
+		}(p)
+	}
+
+	var first error
+	for range processes {
+		if err := <-errs; err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// closeEvents closes the event bus so queued async subscribers finish
+// before the process exits
+func closeEvents(container *di.Container) {
+	bus, err := di.Resolve[*events.Bus](container)
+	if err != nil {
+		log.Printf("Failed to close event bus: %v", err)
+		return
+	}
+	bus.Close()
+}
+
+// closeBroker closes the message broker, draining its subscriptions, once
+// the consumers and the outbox relay have stopped
+func closeBroker(container *di.Container) {
+	broker, err := di.Resolve[messaging.Broker](container)
+	if err != nil {
+		log.Printf("Failed to close message broker: %v", err)
+		return
+	}
+	if err := broker.Close(); err != nil {
+		log.Printf("Failed to close message broker: %v", err)
+	}
+}
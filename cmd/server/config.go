@@ -0,0 +1,52 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// runConfig prints the configuration with secrets redacted, or validates it
+func runConfig(cfg *config.Config, container *di.Container, args []string) error {
+	action := "print"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "print":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(cfg.Redacted())
+	case "validate":
+		if err := cfg.Validate(); err != nil {
+			printValidationErrors(err)
+			return fmt.Errorf("configuration is invalid")
+		}
+		fmt.Println("Configuration is valid")
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q (expected print or validate)", action)
+	}
+}
+
+// printValidationErrors prints one line per invalid field
+func printValidationErrors(err error) {
+	var verrs *utils.ValidationErrors
+	if !errors.As(err, &verrs) {
+		fmt.Fprintln(os.Stderr, err)
+		return
+	}
+	for _, e := range verrs.Errors {
+		if e.Value != nil {
+			fmt.Fprintf(os.Stderr, "  %s: %s (got %v)\n", e.Field, e.Message, e.Value)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", e.Field, e.Message)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+)
+
+// runGraph prints the dependency graph of the container
+func runGraph(cfg *config.Config, container *di.Container, args []string) error {
+	flags := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := flags.String("format", "dot", "output format: dot or json")
+	_ = flags.Parse(args)
+
+	graph := container.Graph()
+
+	switch *format {
+	case "dot":
+		return graph.WriteDOT(os.Stdout)
+	case "json":
+		return graph.WriteJSON(os.Stdout)
+	default:
+		return fmt.Errorf("unknown format %q (expected dot or json)", *format)
+	}
+}
+
+// runDoctor resolves every registered type and fails if any did not resolve
+func runDoctor(cfg *config.Config, container *di.Container, args []string) error {
+	report := container.Doctor()
+	report.Write(os.Stdout)
+
+	if !report.OK() {
+		return fmt.Errorf("%d type(s) failed to resolve", len(report.Failures))
+	}
+	return nil
+}
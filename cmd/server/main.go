@@ -1,20 +1,68 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
+
+	"gorm.io/gorm"
 
-	"github.com/gin-gonic/gin"
 	"github.com/miladev95/golang-project-structure/internal/config"
 	"github.com/miladev95/golang-project-structure/internal/di"
 	"github.com/miladev95/golang-project-structure/internal/di/modules"
-	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 )
 
+// command is a subcommand of the server binary
+type command struct {
+	name  string
+	usage string
+	run   func(cfg *config.Config, container *di.Container, args []string) error
+	// offline commands only inspect the wiring and never connect to the database
+	offline bool
+}
+
+var commands = []command{
+	{"serve", "start the HTTP server (default)", runServe, false},
+	{"worker", "run background jobs, scheduled tasks, the outbox relay and message consumers", runWorker, false},
+	{"migrate", "migrate [up|down|status] - manage the database schema", runMigrate, false},
+	{"seed", "insert sample data", runSeed, false},
+	{"routes", "print every route with its middleware chain", runRoutes, true},
+	{"config", "config [print|validate] - show or check the configuration", runConfig, true},
+	{"graph", "graph [-format dot|json] - print the DI dependency graph", runGraph, true},
+	{"doctor", "resolve every registered type and report failures", runDoctor, false},
+}
+
 func main() {
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+
+	cmd, ok := findCommand(name)
+	if !ok {
+		printUsage()
+		os.Exit(2)
+	}
+
 	// Load configuration
 	cfg := config.LoadConfig()
+	utils.SetStackCapture(cfg.Errors.CaptureStack)
 
 	// Create DI container
+	container, err := newContainer(cfg, cmd.offline)
+	if err != nil {
+		log.Fatalf("Failed to setup dependencies: %v", err)
+	}
+
+	if err := cmd.run(cfg, container, args); err != nil {
+		log.Fatalf("%s: %v", cmd.name, err)
+	}
+}
+
+// newContainer registers all modules and sets up the DI container. When
+// offline is set the database handle never connects.
+func newContainer(cfg *config.Config, offline bool) (*di.Container, error) {
 	container := di.NewContainer()
 
 	// Register modules
@@ -24,47 +72,32 @@ func main() {
 	// .RegisterModule(modules.NewProductModule())
 	// .RegisterModule(modules.NewOrderModule())
 
-	// Setup dependencies
-	if err := container.Setup(cfg); err != nil {
-		log.Fatalf("Failed to setup dependencies: %v", err)
+	if offline {
+		container.Override(func() (*gorm.DB, error) {
+			return config.NewLazyDatabase(cfg)
+		})
 	}
 
-	// Run database migrations
-	db, err := config.NewDatabase(cfg)
-	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
-	}
-	if err := config.RunMigrations(db); err != nil {
-		log.Fatalf("Failed to run migrations: %v", err)
+	// Setup dependencies
+	if err := container.Setup(cfg); err != nil {
+		return nil, err
 	}
 
-	// Create Gin router
-	router := gin.Default()
+	return container, nil
+}
 
-	// Get handlers from container
-	userHandler, err := container.GetUserHandler()
-	if err != nil {
-		log.Fatalf("Failed to get user handler: %v", err)
+func findCommand(name string) (command, bool) {
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd, true
+		}
 	}
+	return command{}, false
+}
 
-	// Register all routes
-	routes.RegisterAll(
-		router,
-		routes.NewUserRouter(userHandler),
-		// routes.NewProductRouter(productHandler), // Add more routers as needed
-		// routes.NewOrderRouter(orderHandler),
-	)
-
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
-
-	// Start server
-	log.Printf("Starting server on %s:%s", cfg.Server.Host, cfg.Server.Port)
-	if err := router.Run(cfg.Server.Host + ":" + cfg.Server.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s <command> [arguments]\n\nCommands:\n", os.Args[0])
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-8s %s\n", cmd.name, cmd.usage)
 	}
 }
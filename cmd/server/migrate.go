@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+)
+
+// runMigrate applies, rolls back or reports on database migrations
+func runMigrate(cfg *config.Config, container *di.Container, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+
+	switch action {
+	case "up":
+		return migrateUp(container)
+	case "down":
+		db, err := di.Resolve[*gorm.DB](container)
+		if err != nil {
+			return err
+		}
+		return config.RollbackMigrations(db)
+	case "status":
+		db, err := di.Resolve[*gorm.DB](container)
+		if err != nil {
+			return err
+		}
+		status := config.CheckMigrationStatus(db)
+		names := make([]string, 0, len(status))
+		for name := range status {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("%-20s %v\n", name, status[name])
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown action %q (expected up, down or status)", action)
+	}
+}
+
+// migrateUp runs all pending migrations against the container's database
+func migrateUp(container *di.Container) error {
+	db, err := di.Resolve[*gorm.DB](container)
+	if err != nil {
+		return err
+	}
+	return config.RunMigrations(db)
+}
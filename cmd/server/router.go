@@ -0,0 +1,118 @@
+package main
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+	"github.com/miladev95/golang-project-structure/internal/health"
+	"github.com/miladev95/golang-project-structure/internal/scheduler"
+)
+
+// newRouter builds the public Gin engine. Operational routes are added too
+// unless the admin listener is enabled. Middleware passed in runs before the
+// global middleware; the routes command uses it to inspect handler chains.
+func newRouter(cfg *config.Config, container *di.Container, middlewares ...gin.HandlerFunc) (*gin.Engine, error) {
+	// Create Gin router
+	router := gin.New()
+	router.Use(middlewares...)
+	router.Use(gin.Logger(), gin.Recovery())
+	router.Use(middleware.ErrorFormatMiddleware(errorFormat(cfg)), middleware.ErrorMiddleware())
+	if cfg.MTLSEnabled() {
+		router.Use(middleware.ClientCertMiddleware())
+	}
+	// After authentication so the scope's context carries the principal
+	router.Use(middleware.RequestScopeMiddleware(container))
+
+	// Get handlers from container
+	userHandler, err := di.Resolve[*http.UserHandler](container)
+	if err != nil {
+		return nil, err
+	}
+
+	// Register all routes
+	routes.RegisterAll(
+		router,
+		routes.NewUserRouter(userHandler, routes.UserCachePolicy{
+			User:  cfg.Cache.UserControl,
+			Users: cfg.Cache.UsersControl,
+		}),
+		// routes.NewProductRouter(productHandler), // Add more routers as needed
+		// routes.NewOrderRouter(orderHandler),
+	)
+
+	if !cfg.AdminEnabled() {
+		adminRouters, err := newAdminRouters(cfg, container)
+		if err != nil {
+			return nil, err
+		}
+		routes.RegisterAll(router, adminRouters...)
+	}
+
+	return router, nil
+}
+
+// newAdminRouter builds the Gin engine for the admin listener. It has no
+// request logging and none of the public middleware.
+func newAdminRouter(cfg *config.Config, container *di.Container, middlewares ...gin.HandlerFunc) (*gin.Engine, error) {
+	router := gin.New()
+	router.Use(middlewares...)
+	router.Use(gin.Recovery())
+	router.Use(middleware.ErrorFormatMiddleware(errorFormat(cfg)))
+
+	adminRouters, err := newAdminRouters(cfg, container)
+	if err != nil {
+		return nil, err
+	}
+	routes.RegisterAll(router, adminRouters...)
+
+	return router, nil
+}
+
+// newAdminRouters returns the operational routers. The admin listener
+// serves health probes, the DI graph, scheduled tasks, metrics and pprof.
+// Without it the public listener serves the health probes, plus the DI
+// graph and scheduled tasks when ADMIN_TOKEN is set; metrics and pprof
+// are never public.
+func newAdminRouters(cfg *config.Config, container *di.Container) ([]routes.Router, error) {
+	healthRegistry, err := di.Resolve[*health.Registry](container)
+	if err != nil {
+		return nil, err
+	}
+
+	taskScheduler, err := di.Resolve[*scheduler.Scheduler](container)
+	if err != nil {
+		return nil, err
+	}
+
+	debugHandler := http.NewDebugHandler(container.Graph())
+	adminRouters := []routes.Router{routes.NewHealthRouter(http.NewHealthHandler(healthRegistry))}
+
+	// Probes stay open; everything else needs the admin token when one is set
+	var auth []gin.HandlerFunc
+	if cfg.Server.AdminToken != "" {
+		auth = append(auth, middleware.AdminTokenMiddleware(cfg.Server.AdminToken))
+	} else if !cfg.AdminEnabled() {
+		return adminRouters, nil
+	}
+
+	adminRouters = append(adminRouters,
+		routes.NewDIRouter(debugHandler, auth...),
+		routes.NewSchedulerRouter(http.NewSchedulerHandler(taskScheduler), auth...),
+	)
+	if cfg.AdminEnabled() {
+		adminRouters = append(adminRouters, routes.NewDebugRouter(debugHandler, auth...))
+	}
+	return adminRouters, nil
+}
+
+// errorFormat returns the error format configured for handlers
+func errorFormat(cfg *config.Config) response.ErrorFormat {
+	return response.ErrorFormat{
+		Problem:     cfg.Errors.Format == "problem",
+		TypeBaseURL: cfg.Errors.ProblemTypeBaseURL,
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+)
+
+var (
+	// pathParamRegex matches :name and *name segments in a route path
+	pathParamRegex = regexp.MustCompile(`[:*][^/]+`)
+	// closureSuffixRegex matches the .func1 suffix of closures
+	closureSuffixRegex = regexp.MustCompile(`(\.func\d+)+$`)
+)
+
+// runRoutes prints every registered route with its middleware chain.
+//
+// Gin does not expose a route's handler chain, so the router is built with
+// an inspecting middleware in front. For each route a request is replayed
+// against the router; the inspector records c.HandlerNames() and aborts
+// before anything else runs.
+func runRoutes(cfg *config.Config, container *di.Container, args []string) error {
+	gin.SetMode(gin.ReleaseMode)
+
+	chains := make(map[string][]string)
+	inspector := func(c *gin.Context) {
+		names := c.HandlerNames()
+		chains[c.Request.Method+" "+c.FullPath()] = names[1:]
+		c.AbortWithStatus(204)
+	}
+
+	router, err := newRouter(cfg, container, inspector)
+	if err != nil {
+		return err
+	}
+	listeners := []struct {
+		name   string
+		router *gin.Engine
+	}{{"public", router}}
+
+	if cfg.AdminEnabled() {
+		adminRouter, err := newAdminRouter(cfg, container, inspector)
+		if err != nil {
+			return err
+		}
+		listeners = append(listeners, struct {
+			name   string
+			router *gin.Engine
+		}{"admin", adminRouter})
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LISTENER\tMETHOD\tPATH\tHANDLER CHAIN")
+	for _, l := range listeners {
+		for _, route := range l.router.Routes() {
+			path := pathParamRegex.ReplaceAllString(route.Path, "x")
+			l.router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(route.Method, path, nil))
+
+			chain, ok := chains[route.Method+" "+route.Path]
+			if !ok {
+				chain = []string{route.Handler}
+			}
+			delete(chains, route.Method+" "+route.Path)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", l.name, route.Method, route.Path, formatChain(chain))
+		}
+	}
+	return w.Flush()
+}
+
+// formatChain shortens handler names to package.Function and joins them
+func formatChain(names []string) string {
+	short := make([]string, len(names))
+	for i, name := range names {
+		if j := strings.LastIndex(name, "/"); j >= 0 {
+			name = name[j+1:]
+		}
+		short[i] = strings.TrimSuffix(closureSuffixRegex.ReplaceAllString(name, ""), "-fm")
+	}
+	return strings.Join(short, " → ")
+}
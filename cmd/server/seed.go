@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/internal/services"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// seedUsers are inserted by the seed command when missing
+var seedUsers = []models.User{
+	{Name: "Alice Example", Email: "alice@example.com"},
+	{Name: "Bob Example", Email: "bob@example.com"},
+	{Name: "Carol Example", Email: "carol@example.com"},
+}
+
+// runSeed inserts sample data through the services, so business rules apply.
+// Users whose email already exists are skipped.
+func runSeed(cfg *config.Config, container *di.Container, args []string) error {
+	userService, err := di.Resolve[services.UserService](container)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	for _, u := range seedUsers {
+		_, existing, err := userService.ListUsers(ctx, repositories.UserQuery{
+			Pagination: utils.NewPagination(1, 1, 0),
+			Filter:     repositories.UserFilter{Email: u.Email},
+		})
+		if err != nil {
+			return err
+		}
+		if existing > 0 {
+			continue
+		}
+		user := u
+		if _, err := userService.CreateUser(ctx, &user); err != nil {
+			return err
+		}
+		log.Printf("✅ Seeded user %s", user.Email)
+	}
+	return nil
+}
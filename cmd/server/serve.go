@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/server"
+)
+
+// runServe migrates the database and starts the HTTP server. On SIGINT or
+// SIGTERM the listeners stop accepting requests, in-flight requests and
+// background processes finish, queued async event subscribers drain and the
+// message broker is closed.
+func runServe(cfg *config.Config, container *di.Container, args []string) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Resolve every registered type up front so all wiring errors show at once
+	if cfg.DI.Doctor {
+		if report := container.Doctor(); !report.OK() {
+			report.Write(os.Stderr)
+			return report.Err()
+		}
+	}
+
+	// Run database migrations
+	if err := migrateUp(container); err != nil {
+		return err
+	}
+
+	router, err := newRouter(cfg, container)
+	if err != nil {
+		return err
+	}
+
+	publicServer, err := server.NewPublic(cfg, router)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errs := make(chan error, 3)
+	var servers []*server.Server
+
+	processes, err := backgroundProcesses(cfg, container, cfg.Jobs.Embedded)
+	if err != nil {
+		return err
+	}
+	backgroundDone := make(chan struct{})
+	go func() {
+		defer close(backgroundDone)
+		if err := runBackground(ctx, processes); err != nil {
+			errs <- err
+		}
+	}()
+
+	if cfg.AdminEnabled() {
+		adminRouter, err := newAdminRouter(cfg, container)
+		if err != nil {
+			return err
+		}
+
+		adminServer := server.NewAdmin(cfg, adminRouter)
+		servers = append(servers, adminServer)
+		go func() {
+			log.Printf("Starting admin server on %s", adminServer.Addr)
+			errs <- fmt.Errorf("admin server: %w", adminServer.ListenAndServe())
+		}()
+	}
+
+	// Start server
+	servers = append(servers, publicServer)
+	go func() {
+		switch {
+		case publicServer.TLSConfig != nil:
+			log.Printf("Starting server on %s %s (TLS, mTLS: %t)", publicServer.Network, publicServer.Addr, cfg.MTLSEnabled())
+		case cfg.Server.H2C:
+			log.Printf("Starting server on %s %s (h2c)", publicServer.Network, publicServer.Addr)
+		default:
+			log.Printf("Starting server on %s %s", publicServer.Network, publicServer.Addr)
+		}
+		errs <- publicServer.ListenAndServe()
+	}()
+
+	// A signal or either listener failing stops the process
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		log.Println("Shutting down")
+	}
+	stop()
+
+	// Requests can't outlast the write timeout, so it bounds the shutdown
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.WriteTimeout)
+	defer cancel()
+	for _, srv := range servers {
+		if shutdownErr := srv.Shutdown(shutdownCtx); shutdownErr != nil {
+			log.Printf("Failed to shut down server on %s: %v", srv.Addr, shutdownErr)
+		}
+	}
+	<-backgroundDone
+	closeEvents(container)
+	closeBroker(container)
+
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+)
+
+// runWorker runs background jobs, plus scheduled tasks and the outbox relay
+// when enabled, until the process is interrupted. Running jobs and tasks,
+// and queued async event subscribers, finish and the message broker is
+// closed before it exits.
+func runWorker(cfg *config.Config, container *di.Container, args []string) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	processes, err := backgroundProcesses(cfg, container, true)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	log.Printf("Starting %d job workers", cfg.Jobs.Workers)
+	err = runBackground(ctx, processes)
+	closeEvents(container)
+	closeBroker(container)
+	log.Println("Workers stopped")
+	return err
+}
@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"crypto/x509"
+)
+
+// Principal is the authenticated caller of a request
+type Principal struct {
+	// Subject is the distinguished name of the caller
+	Subject string
+	// CommonName is the CN from the subject
+	CommonName string
+	// Certificate is the verified client certificate, for mTLS callers
+	Certificate *x509.Certificate
+}
+
+type contextKey struct{}
+
+// FromCertificate builds the principal for a verified client certificate
+func FromCertificate(cert *x509.Certificate) *Principal {
+	return &Principal{
+		Subject:     cert.Subject.String(),
+		CommonName:  cert.Subject.CommonName,
+		Certificate: cert,
+	}
+}
+
+// NewContext returns a copy of ctx carrying the principal
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the principal stored in ctx, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
@@ -14,6 +16,41 @@ type Config struct {
 	Server struct {
 		Host string
 		Port string
+		// AdminHost and AdminPort configure a second listener for health,
+		// metrics, pprof and other operational routes. With AdminPort empty
+		// the public listener serves the health probes, the DI graph and
+		// scheduler routes only with AdminToken set, and never metrics or
+		// pprof.
+		AdminHost string
+		AdminPort string
+		// AdminToken, when set, is required as a bearer token on admin routes
+		// other than the health probes
+		AdminToken string
+		// Socket, when set, is a Unix domain socket path the public listener
+		// uses instead of Host and Port
+		Socket string
+		// H2C serves cleartext HTTP/2 alongside HTTP/1.1; only without TLS
+		H2C bool
+		// Timeouts and limits of the HTTP servers; see net/http.Server
+		ReadTimeout       time.Duration
+		ReadHeaderTimeout time.Duration
+		WriteTimeout      time.Duration
+		IdleTimeout       time.Duration
+		MaxHeaderBytes    int
+	}
+	TLS struct {
+		// CertFile and KeyFile enable TLS on the public listener. The files
+		// are reloaded when they change on disk.
+		CertFile string
+		KeyFile  string
+		// MinVersion is the lowest accepted protocol version: 1.0 to 1.3
+		MinVersion string
+		// CipherSuites restricts TLS 1.2 and older to the named suites;
+		// empty uses Go's defaults
+		CipherSuites []string
+		// ClientCAFile enables mTLS: clients must present a certificate
+		// signed by a CA in this bundle
+		ClientCAFile string
 	}
 	Database struct {
 		Driver   string
@@ -23,6 +60,93 @@ type Config struct {
 		Password string
 		DBName   string
 	}
+	Health struct {
+		// Timeout bounds each individual check
+		Timeout time.Duration
+		// CacheTTL is how long a check result is reused; 0 disables caching
+		CacheTTL time.Duration
+	}
+	Jobs struct {
+		// Workers is the number of jobs a worker process runs in parallel
+		Workers int
+		// PollInterval is how often an idle worker checks for due jobs
+		PollInterval time.Duration
+		// LockTimeout is how long a job may run before it is handed to
+		// another worker
+		LockTimeout time.Duration
+		// MaxAttempts is the default number of attempts before a job is
+		// dead-lettered
+		MaxAttempts int
+		// Embedded runs the workers inside the serve command as well
+		Embedded bool
+	}
+	Scheduler struct {
+		// Enabled runs scheduled tasks in the serve and worker commands
+		Enabled bool
+		// LeaseTTL is how long a crashed instance can hold a task's lease
+		LeaseTTL time.Duration
+		// Timezone is the IANA zone cron expressions are evaluated in
+		Timezone string
+	}
+	Outbox struct {
+		// RelayEnabled publishes outbox messages from the serve and worker
+		// commands
+		RelayEnabled bool
+		// Publisher selects where messages go: log, webhook or broker
+		Publisher string
+		// WebhookURL receives a POST per message when Publisher is webhook
+		WebhookURL string
+		// WebhookSecret, when set, signs webhook bodies with HMAC-SHA256
+		WebhookSecret string
+		// BatchSize is the number of messages published per poll
+		BatchSize int
+		// PollInterval is how often an idle relay checks for messages
+		PollInterval time.Duration
+		// LeaseTTL is how long a relay holds the messages it is publishing
+		LeaseTTL time.Duration
+	}
+	Messaging struct {
+		// Driver selects the message broker: memory or nats
+		Driver string
+		// NATSURL is the NATS server when Driver is nats
+		NATSURL string
+		// NATSStream is the JetStream stream events are stored in; it is
+		// created with NATSSubjects when missing
+		NATSStream   string
+		NATSSubjects []string
+		// AckWait is how long a consumer has to handle an event before it
+		// is redelivered
+		AckWait time.Duration
+		// MaxDeliver caps deliveries of a failing event; 0 is unlimited
+		MaxDeliver int
+	}
+	Errors struct {
+		// CaptureStack records a stack trace in application errors, printed
+		// when they are logged with %+v
+		CaptureStack bool
+		// Format is the error body: envelope (default) or problem for
+		// application/problem+json. Clients can ask for problem with Accept.
+		Format string
+		// ProblemTypeBaseURL prefixes problem types; empty means about:blank
+		ProblemTypeBaseURL string
+	}
+	Cache struct {
+		// UserControl and UsersControl are the Cache-Control of
+		// GET /api/v1/users/:id and GET /api/v1/users; empty sends none
+		UserControl  string
+		UsersControl string
+	}
+	Events struct {
+		// AsyncWorkers is the number of goroutines running async subscribers
+		AsyncWorkers int
+		// BufferSize is how many events each worker queues before
+		// publishers block
+		BufferSize int
+	}
+	DI struct {
+		// Doctor resolves every registered type at startup and reports all failures
+		Doctor bool
+	}
 }
 
 // LoadConfig loads configuration from environment variables
@@ -32,6 +156,23 @@ func LoadConfig() *Config {
 	// Server config
 	cfg.Server.Host = getEnv("SERVER_HOST", "0.0.0.0")
 	cfg.Server.Port = getEnv("SERVER_PORT", "8080")
+	cfg.Server.AdminHost = getEnv("ADMIN_HOST", "127.0.0.1")
+	cfg.Server.AdminPort = getEnv("ADMIN_PORT", "")
+	cfg.Server.AdminToken = getEnv("ADMIN_TOKEN", "")
+	cfg.Server.Socket = getEnv("SERVER_SOCKET", "")
+	cfg.Server.H2C = getEnvBool("SERVER_H2C", false)
+	cfg.Server.ReadTimeout = getEnvDuration("SERVER_READ_TIMEOUT", 15*time.Second)
+	cfg.Server.ReadHeaderTimeout = getEnvDuration("SERVER_READ_HEADER_TIMEOUT", 5*time.Second)
+	cfg.Server.WriteTimeout = getEnvDuration("SERVER_WRITE_TIMEOUT", 30*time.Second)
+	cfg.Server.IdleTimeout = getEnvDuration("SERVER_IDLE_TIMEOUT", 120*time.Second)
+	cfg.Server.MaxHeaderBytes = getEnvInt("SERVER_MAX_HEADER_BYTES", 1<<20)
+
+	// TLS config
+	cfg.TLS.CertFile = getEnv("TLS_CERT_FILE", "")
+	cfg.TLS.KeyFile = getEnv("TLS_KEY_FILE", "")
+	cfg.TLS.MinVersion = getEnv("TLS_MIN_VERSION", "1.2")
+	cfg.TLS.CipherSuites = getEnvList("TLS_CIPHER_SUITES", nil)
+	cfg.TLS.ClientCAFile = getEnv("TLS_CLIENT_CA_FILE", "")
 
 	// Database config
 	cfg.Database.Driver = getEnv("DB_DRIVER", "postgres")
@@ -41,22 +182,82 @@ func LoadConfig() *Config {
 	cfg.Database.Password = getEnv("DB_PASSWORD", "")
 	cfg.Database.DBName = getEnv("DB_NAME", "myapp")
 
+	// Health config
+	cfg.Health.Timeout = getEnvDuration("HEALTH_TIMEOUT", 2*time.Second)
+	cfg.Health.CacheTTL = getEnvDuration("HEALTH_CACHE_TTL", time.Second)
+
+	// Jobs config
+	cfg.Jobs.Workers = getEnvInt("JOBS_WORKERS", 4)
+	cfg.Jobs.PollInterval = getEnvDuration("JOBS_POLL_INTERVAL", time.Second)
+	cfg.Jobs.LockTimeout = getEnvDuration("JOBS_LOCK_TIMEOUT", 5*time.Minute)
+	cfg.Jobs.MaxAttempts = getEnvInt("JOBS_MAX_ATTEMPTS", 5)
+	cfg.Jobs.Embedded = getEnvBool("JOBS_EMBEDDED", false)
+
+	// Scheduler config
+	cfg.Scheduler.Enabled = getEnvBool("SCHEDULER_ENABLED", true)
+	cfg.Scheduler.LeaseTTL = getEnvDuration("SCHEDULER_LEASE_TTL", 10*time.Minute)
+	cfg.Scheduler.Timezone = getEnv("SCHEDULER_TIMEZONE", "UTC")
+
+	// Outbox config
+	cfg.Outbox.RelayEnabled = getEnvBool("OUTBOX_RELAY_ENABLED", true)
+	cfg.Outbox.Publisher = getEnv("OUTBOX_PUBLISHER", "broker")
+	cfg.Outbox.WebhookURL = getEnv("OUTBOX_WEBHOOK_URL", "")
+	cfg.Outbox.WebhookSecret = getEnv("OUTBOX_WEBHOOK_SECRET", "")
+	cfg.Outbox.BatchSize = getEnvInt("OUTBOX_BATCH_SIZE", 100)
+	cfg.Outbox.PollInterval = getEnvDuration("OUTBOX_POLL_INTERVAL", time.Second)
+	cfg.Outbox.LeaseTTL = getEnvDuration("OUTBOX_LEASE_TTL", 5*time.Minute)
+
+	// Messaging config
+	cfg.Messaging.Driver = getEnv("MESSAGING_DRIVER", "memory")
+	cfg.Messaging.NATSURL = getEnv("MESSAGING_NATS_URL", "nats://127.0.0.1:4222")
+	cfg.Messaging.NATSStream = getEnv("MESSAGING_NATS_STREAM", "EVENTS")
+	cfg.Messaging.NATSSubjects = getEnvList("MESSAGING_NATS_SUBJECTS", []string{"user.>"})
+	cfg.Messaging.AckWait = getEnvDuration("MESSAGING_ACK_WAIT", 30*time.Second)
+	cfg.Messaging.MaxDeliver = getEnvInt("MESSAGING_MAX_DELIVER", 0)
+
+	// Errors config
+	cfg.Errors.CaptureStack = getEnvBool("ERRORS_CAPTURE_STACK", false)
+	cfg.Errors.Format = getEnv("ERRORS_FORMAT", "envelope")
+	cfg.Errors.ProblemTypeBaseURL = getEnv("ERRORS_PROBLEM_TYPE_BASE_URL", "")
+
+	// Cache config
+	cfg.Cache.UserControl = getEnv("CACHE_CONTROL_USER", "private, no-cache")
+	cfg.Cache.UsersControl = getEnv("CACHE_CONTROL_USERS", "private, no-cache")
+
+	// Events config
+	cfg.Events.AsyncWorkers = getEnvInt("EVENTS_ASYNC_WORKERS", 4)
+	cfg.Events.BufferSize = getEnvInt("EVENTS_BUFFER_SIZE", 256)
+
+	// DI config
+	cfg.DI.Doctor = getEnvBool("DI_DOCTOR", false)
+
 	return cfg
 }
 
 // NewDatabase creates a new database connection
 func NewDatabase(cfg *Config) (*gorm.DB, error) {
+	return openDatabase(cfg, &gorm.Config{})
+}
+
+// NewLazyDatabase creates a database handle without connecting; the
+// connection is opened by the first query. Tooling that only inspects the
+// application's wiring uses it so it can run without a database.
+func NewLazyDatabase(cfg *Config) (*gorm.DB, error) {
+	return openDatabase(cfg, &gorm.Config{DisableAutomaticPing: true})
+}
+
+func openDatabase(cfg *Config, gormCfg *gorm.Config) (*gorm.DB, error) {
 	switch cfg.Database.Driver {
 	case "mysql":
-		return connectMySQL(cfg)
+		return connectMySQL(cfg, gormCfg)
 	case "postgres":
-		return connectPostgres(cfg)
+		return connectPostgres(cfg, gormCfg)
 	default:
-		return connectPostgres(cfg)
+		return connectPostgres(cfg, gormCfg)
 	}
 }
 
-func connectPostgres(cfg *Config) (*gorm.DB, error) {
+func connectPostgres(cfg *Config, gormCfg *gorm.Config) (*gorm.DB, error) {
 	dsn := "host=" + cfg.Database.Host +
 		" port=" + strconv.Itoa(cfg.Database.Port) +
 		" user=" + cfg.Database.User +
@@ -64,15 +265,15 @@ func connectPostgres(cfg *Config) (*gorm.DB, error) {
 		" dbname=" + cfg.Database.DBName +
 		" sslmode=disable"
 
-	return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	return gorm.Open(postgres.Open(dsn), gormCfg)
 }
 
-func connectMySQL(cfg *Config) (*gorm.DB, error) {
+func connectMySQL(cfg *Config, gormCfg *gorm.Config) (*gorm.DB, error) {
 	dsn := cfg.Database.User + ":" + cfg.Database.Password +
 		"@tcp(" + cfg.Database.Host + ":" + strconv.Itoa(cfg.Database.Port) + ")/" +
 		cfg.Database.DBName + "?charset=utf8mb4&parseTime=True&loc=Local"
 
-	return gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	return gorm.Open(mysql.Open(dsn), gormCfg)
 }
 
 func getEnv(key, defaultVal string) string {
@@ -89,4 +290,35 @@ func getEnvInt(key string, defaultVal int) int {
 		}
 	}
 	return defaultVal
-}
\ No newline at end of file
+}
+
+func getEnvBool(key string, defaultVal bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultVal
+}
+
+func getEnvList(key string, defaultVal []string) []string {
+	if value := os.Getenv(key); value != "" {
+		var list []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+		return list
+	}
+	return defaultVal
+}
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if durationVal, err := time.ParseDuration(value); err == nil {
+			return durationVal
+		}
+	}
+	return defaultVal
+}
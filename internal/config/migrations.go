@@ -1,18 +1,232 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// Migration is a versioned schema change
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(db *gorm.DB) error
+	Down    func(db *gorm.DB) error
+}
+
+// SchemaMigration records an applied migration in the
+// app_schema_migrations table
+type SchemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	AppliedAt time.Time
+}
+
+// TableName returns the migrations bookkeeping table name. It differs from
+// golang-migrate's schema_migrations so the two never share a table.
+func (SchemaMigration) TableName() string {
+	return "app_schema_migrations"
+}
+
+// legacyMigrationsTable is the name the bookkeeping table had before it
+// was renamed; golang-migrate uses the same name
+const legacyMigrationsTable = "schema_migrations"
+
+// usesGolangMigrate reports whether golang-migrate manages the schema: its
+// schema_migrations table exists and this runner has no table of its own
+func usesGolangMigrate(db *gorm.DB) bool {
+	return !db.Migrator().HasTable(&SchemaMigration{}) &&
+		db.Migrator().HasColumn(legacyMigrationsTable, "dirty")
+}
+
+// renameLegacyMigrationsTable moves this runner's bookkeeping out of
+// schema_migrations. golang-migrate's table, which has no name column, is
+// left alone.
+func renameLegacyMigrationsTable(db *gorm.DB) error {
+	m := db.Migrator()
+	if m.HasTable(&SchemaMigration{}) || !m.HasColumn(legacyMigrationsTable, "name") {
+		return nil
+	}
+	if err := m.RenameTable(legacyMigrationsTable, &SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to rename %s table: %w", legacyMigrationsTable, err)
+	}
+	log.Printf("✅ Renamed %s to %s", legacyMigrationsTable, SchemaMigration{}.TableName())
+	return nil
+}
+
+// golangMigrateVersion reads the version golang-migrate recorded
+func golangMigrateVersion(db *gorm.DB) (int, error) {
+	var row struct {
+		Version int
+		Dirty   bool
+	}
+	err := db.Table(legacyMigrationsTable).Select("version, dirty").Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read golang-migrate version: %w", err)
+	}
+	if row.Dirty {
+		return 0, fmt.Errorf("golang-migrate version %d is dirty; fix it with migrate force", row.Version)
+	}
+	return row.Version, nil
+}
+
+// migrations lists every schema change in version order.
+// Append new migrations at the end; never edit one that has been released.
+var migrations = []Migration{
+	{Version: 1, Name: "create_users_table", Up: createUsersTable, Down: dropUsersTable},
+	{Version: 2, Name: "create_jobs_table", Up: createJobsTable, Down: dropJobsTable},
+	{Version: 3, Name: "create_scheduler_tasks_table", Up: createSchedulerTasksTable, Down: dropSchedulerTasksTable},
+	{Version: 4, Name: "create_outbox_table", Up: createOutboxTable, Down: dropOutboxTable},
+	{Version: 5, Name: "create_processed_events_table", Up: createProcessedEventsTable, Down: dropProcessedEventsTable},
+	{Version: 6, Name: "add_users_version_column", Up: addUsersVersionColumn, Down: dropUsersVersionColumn},
+}
+
 // RunMigrations runs all pending migrations
 // This is a simple alternative to golang-migrate tool
-// For production, use golang-migrate/migrate instead
+// For production, use golang-migrate/migrate instead. On a database it
+// manages, RunMigrations only reports the version it finds.
 func RunMigrations(db *gorm.DB) error {
 	log.Println("🔄 Running database migrations...")
 
+	if usesGolangMigrate(db) {
+		version, err := golangMigrateVersion(db)
+		if err != nil {
+			return err
+		}
+		if version < LatestMigrationVersion() {
+			log.Printf("⚠️  golang-migrate is at version %d, latest is %d; apply migrations/*.sql", version, LatestMigrationVersion())
+		} else {
+			log.Printf("✅ Schema managed by golang-migrate at version %d", version)
+		}
+		return nil
+	}
+
+	if err := renameLegacyMigrationsTable(db); err != nil {
+		return err
+	}
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create %s table: %w", SchemaMigration{}.TableName(), err)
+	}
+
+	current, err := CurrentMigrationVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		log.Printf("✅ Applied migration %d: %s", m.Version, m.Name)
+	}
+
+	log.Println("✅ All migrations completed successfully")
+	return nil
+}
+
+// RollbackMigrations rolls back all migrations
+// Use with caution! This will delete all data
+func RollbackMigrations(db *gorm.DB) error {
+	log.Println("⚠️  Rolling back migrations...")
+
+	if usesGolangMigrate(db) {
+		return errors.New("schema is managed by golang-migrate; roll back with migrate down")
+	}
+
+	current, err := CurrentMigrationVersion(db)
+	if err != nil {
+		return err
+	}
+
+	for i := len(migrations) - 1; i >= 0; i-- {
+		m := migrations[i]
+		if m.Version > current {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			if !tx.Migrator().HasTable(&SchemaMigration{}) {
+				return nil
+			}
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return fmt.Errorf("rollback of migration %d (%s) failed: %w", m.Version, m.Name, err)
+		}
+		log.Printf("✅ Rolled back migration %d: %s", m.Version, m.Name)
+	}
+
+	log.Println("✅ Migrations rolled back")
+	return nil
+}
+
+// CheckMigrationStatus returns current migration status
+func CheckMigrationStatus(db *gorm.DB) map[string]bool {
+	status := make(map[string]bool)
+
+	status["users_table"] = db.Migrator().HasTable("users")
+	status["users_email_index"] = db.Migrator().HasIndex("users", "email")
+	status["jobs_table"] = db.Migrator().HasTable("jobs")
+	status["scheduler_tasks_table"] = db.Migrator().HasTable("scheduler_tasks")
+	status["outbox_table"] = db.Migrator().HasTable("outbox")
+	status["processed_events_table"] = db.Migrator().HasTable("processed_events")
+	status["users_version_column"] = db.Migrator().HasColumn("users", "version")
+
+	current, err := CurrentMigrationVersion(db)
+	status["schema_up_to_date"] = err == nil && current == LatestMigrationVersion()
+
+	return status
+}
+
+// CurrentMigrationVersion returns the highest applied migration version,
+// or 0 when none has been applied. On a database managed by golang-migrate
+// it returns golang-migrate's version.
+func CurrentMigrationVersion(db *gorm.DB) (int, error) {
+	if usesGolangMigrate(db) {
+		return golangMigrateVersion(db)
+	}
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return 0, nil
+	}
+
+	var version *int
+	if err := db.Model(&SchemaMigration{}).Select("MAX(version)").Scan(&version).Error; err != nil {
+		return 0, fmt.Errorf("failed to read migration version: %w", err)
+	}
+	if version == nil {
+		return 0, nil
+	}
+	return *version, nil
+}
+
+// LatestMigrationVersion returns the version of the newest known migration
+func LatestMigrationVersion() int {
+	if len(migrations) == 0 {
+		return 0
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+func createUsersTable(db *gorm.DB) error {
 	// Create users table
 	if !db.Migrator().HasTable("users") {
 		if err := db.Exec(`
@@ -37,29 +251,165 @@ func RunMigrations(db *gorm.DB) error {
 		log.Println("✅ Created index on users.email")
 	}
 
-	log.Println("✅ All migrations completed successfully")
 	return nil
 }
 
-// RollbackMigrations rolls back all migrations
-// Use with caution! This will delete all data
-func RollbackMigrations(db *gorm.DB) error {
-	log.Println("⚠️  Rolling back migrations...")
-
+func dropUsersTable(db *gorm.DB) error {
 	if err := db.Migrator().DropTable("users"); err != nil {
 		return fmt.Errorf("failed to drop users table: %w", err)
 	}
+	return nil
+}
 
-	log.Println("✅ Migrations rolled back")
+// jobsTable is the jobs table as created by migration 2. Migrations keep
+// their own copy of a table's shape so later model changes don't alter them.
+type jobsTable struct {
+	ID          int64     `gorm:"primaryKey"`
+	Name        string    `gorm:"size:255;not null"`
+	Payload     string    `gorm:"type:text"`
+	Status      string    `gorm:"size:16;not null;index:idx_jobs_status_run_at,priority:1"`
+	Attempts    int       `gorm:"not null;default:0"`
+	MaxAttempts int       `gorm:"not null"`
+	RunAt       time.Time `gorm:"not null;index:idx_jobs_status_run_at,priority:2"`
+	LastError   string    `gorm:"type:text"`
+	LockedBy    string    `gorm:"size:255"`
+	LockedAt    *time.Time
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (jobsTable) TableName() string {
+	return "jobs"
+}
+
+func createJobsTable(db *gorm.DB) error {
+	if err := db.Migrator().CreateTable(&jobsTable{}); err != nil {
+		return fmt.Errorf("failed to create jobs table: %w", err)
+	}
+	log.Println("✅ Created jobs table")
 	return nil
 }
 
-// CheckMigrationStatus returns current migration status
-func CheckMigrationStatus(db *gorm.DB) map[string]bool {
-	status := make(map[string]bool)
+func dropJobsTable(db *gorm.DB) error {
+	if err := db.Migrator().DropTable(&jobsTable{}); err != nil {
+		return fmt.Errorf("failed to drop jobs table: %w", err)
+	}
+	return nil
+}
 
-	status["users_table"] = db.Migrator().HasTable("users")
-	status["users_email_index"] = db.Migrator().HasIndex("users", "email")
+// schedulerTasksTable is the scheduler_tasks table as created by migration 3
+type schedulerTasksTable struct {
+	Name            string `gorm:"primaryKey;size:255"`
+	LastScheduledAt *time.Time
+	LeaseHolder     string `gorm:"size:255"`
+	LeaseUntil      *time.Time
+	LastRunAt       *time.Time
+	LastDurationMs  int64  `gorm:"not null;default:0"`
+	LastError       string `gorm:"type:text"`
+	UpdatedAt       time.Time
+}
 
-	return status
-}
\ No newline at end of file
+func (schedulerTasksTable) TableName() string {
+	return "scheduler_tasks"
+}
+
+func createSchedulerTasksTable(db *gorm.DB) error {
+	if err := db.Migrator().CreateTable(&schedulerTasksTable{}); err != nil {
+		return fmt.Errorf("failed to create scheduler_tasks table: %w", err)
+	}
+	log.Println("✅ Created scheduler_tasks table")
+	return nil
+}
+
+func dropSchedulerTasksTable(db *gorm.DB) error {
+	if err := db.Migrator().DropTable(&schedulerTasksTable{}); err != nil {
+		return fmt.Errorf("failed to drop scheduler_tasks table: %w", err)
+	}
+	return nil
+}
+
+// outboxTable is the outbox table as created by migration 4
+type outboxTable struct {
+	ID            int64     `gorm:"primaryKey"`
+	Topic         string    `gorm:"size:255;not null"`
+	AggregateID   string    `gorm:"size:255"`
+	Payload       string    `gorm:"type:text"`
+	Status        string    `gorm:"size:16;not null;index:idx_outbox_status_next_attempt_at,priority:1"`
+	Attempts      int       `gorm:"not null;default:0"`
+	NextAttemptAt time.Time `gorm:"not null;index:idx_outbox_status_next_attempt_at,priority:2"`
+	LastError     string    `gorm:"type:text"`
+	CreatedAt     time.Time
+	SentAt        *time.Time
+}
+
+func (outboxTable) TableName() string {
+	return "outbox"
+}
+
+func createOutboxTable(db *gorm.DB) error {
+	if err := db.Migrator().CreateTable(&outboxTable{}); err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	log.Println("✅ Created outbox table")
+	return nil
+}
+
+func dropOutboxTable(db *gorm.DB) error {
+	if err := db.Migrator().DropTable(&outboxTable{}); err != nil {
+		return fmt.Errorf("failed to drop outbox table: %w", err)
+	}
+	return nil
+}
+
+// processedEventsTable is the processed_events table as created by migration 5
+type processedEventsTable struct {
+	Consumer    string    `gorm:"primaryKey;size:255"`
+	EventID     string    `gorm:"primaryKey;size:64"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+func (processedEventsTable) TableName() string {
+	return "processed_events"
+}
+
+func createProcessedEventsTable(db *gorm.DB) error {
+	if err := db.Migrator().CreateTable(&processedEventsTable{}); err != nil {
+		return fmt.Errorf("failed to create processed_events table: %w", err)
+	}
+	log.Println("✅ Created processed_events table")
+	return nil
+}
+
+func dropProcessedEventsTable(db *gorm.DB) error {
+	if err := db.Migrator().DropTable(&processedEventsTable{}); err != nil {
+		return fmt.Errorf("failed to drop processed_events table: %w", err)
+	}
+	return nil
+}
+
+// usersVersionColumn is the version column migration 6 adds to users
+type usersVersionColumn struct {
+	Version int64 `gorm:"not null;default:1"`
+}
+
+func (usersVersionColumn) TableName() string {
+	return "users"
+}
+
+func addUsersVersionColumn(db *gorm.DB) error {
+	if db.Migrator().HasColumn(&usersVersionColumn{}, "Version") {
+		return nil
+	}
+	if err := db.Migrator().AddColumn(&usersVersionColumn{}, "Version"); err != nil {
+		return fmt.Errorf("failed to add users.version column: %w", err)
+	}
+	log.Println("✅ Added users.version column")
+	return nil
+}
+
+func dropUsersVersionColumn(db *gorm.DB) error {
+	if err := db.Migrator().DropColumn(&usersVersionColumn{}, "Version"); err != nil {
+		return fmt.Errorf("failed to drop users.version column: %w", err)
+	}
+	return nil
+}
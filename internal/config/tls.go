@@ -0,0 +1,52 @@
+package config
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// tlsVersions maps TLS_MIN_VERSION values to crypto/tls versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// TLSEnabled reports whether the public listener serves TLS
+func (c *Config) TLSEnabled() bool {
+	return c.TLS.CertFile != "" || c.TLS.KeyFile != ""
+}
+
+// MTLSEnabled reports whether clients must present a verified certificate
+func (c *Config) MTLSEnabled() bool {
+	return c.TLS.ClientCAFile != ""
+}
+
+// ParseTLSVersion converts a version such as "1.2" to its crypto/tls constant
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q", version)
+	}
+	return v, nil
+}
+
+// ParseCipherSuites converts cipher suite names, as listed by
+// tls.CipherSuites, to their IDs. Insecure suites are rejected.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	available := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		available[suite.Name] = suite.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	for _, name := range names {
+		id, ok := available[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown or insecure cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
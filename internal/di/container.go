@@ -1,17 +1,33 @@
 package di
 
 import (
+	"sync"
+
 	"go.uber.org/dig"
 
 	"github.com/miladev95/golang-project-structure/internal/config"
 	"github.com/miladev95/golang-project-structure/internal/di/modules"
-	"github.com/miladev95/golang-project-structure/internal/handlers/http"
 )
 
+// CoreModule is the module name recorded for providers registered by the
+// container itself (configuration, database)
+const CoreModule = "core"
+
 // Container represents the dependency injection container
 type Container struct {
 	*dig.Container
 	moduleRegistry *modules.Registry
+	overrides      []interface{}
+
+	// parent is set on request scopes and points at the root container
+	parent *Container
+	// mu serializes resolution from request scopes into the root container,
+	// and bridging into a request scope
+	mu          sync.Mutex
+	bridgeOnce  sync.Once
+	bridgeIndex map[scopeKey]bridge
+	// scopeKeys is set on request scopes and holds the values they provide
+	scopeKeys map[scopeKey]bool
 }
 
 // NewContainer creates a new DI container
@@ -39,23 +55,41 @@ func (c *Container) Setup(cfg *config.Config) error {
 		return err
 	}
 
+	if err := c.ProvideHealth(cfg); err != nil {
+		return err
+	}
+
+	if err := c.ProvideMessaging(cfg); err != nil {
+		return err
+	}
+
+	if err := c.ProvideEvents(cfg); err != nil {
+		return err
+	}
+
+	if err := c.ProvideJobs(cfg); err != nil {
+		return err
+	}
+
+	if err := c.ProvideScheduler(cfg); err != nil {
+		return err
+	}
+
+	if err := c.ProvideOutbox(cfg); err != nil {
+		return err
+	}
+
 	// Setup all registered modules
 	if err := c.moduleRegistry.Setup(c.Container); err != nil {
 		return err
 	}
 
-	return nil
-}
-
-// GetUserHandler resolves and returns UserHandler
-func (c *Container) GetUserHandler() (*http.UserHandler, error) {
-	var handler *http.UserHandler
-	if err := c.Invoke(func(h *http.UserHandler) {
-		handler = h
-	}); err != nil {
-		return nil, err
+	// Apply test overrides last so they replace module providers
+	if err := c.applyOverrides(); err != nil {
+		return err
 	}
-	return handler, nil
+
+	return nil
 }
 
 // GetModule returns a module by name (for inspection)
@@ -67,3 +101,8 @@ func (c *Container) GetModule(name string) modules.Module {
 	}
 	return nil
 }
+
+// core returns a binder that records providers against the core module
+func (c *Container) core() *modules.Binder {
+	return modules.NewBinder(c.Container, CoreModule, c.moduleRegistry)
+}
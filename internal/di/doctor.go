@@ -0,0 +1,89 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// DoctorReport is the result of resolving every registered type
+type DoctorReport struct {
+	Resolved []string
+	Skipped  []string
+	Failures []*ResolveError
+}
+
+// OK reports whether every registered type resolved
+func (r *DoctorReport) OK() bool {
+	return len(r.Failures) == 0
+}
+
+// Err joins all failures into one error, or returns nil when OK
+func (r *DoctorReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	errs := make([]error, len(r.Failures))
+	for i, f := range r.Failures {
+		errs[i] = f
+	}
+	return errors.Join(errs...)
+}
+
+// Write prints a human readable summary of the report
+func (r *DoctorReport) Write(w io.Writer) {
+	for _, key := range r.Resolved {
+		fmt.Fprintf(w, "  ok    %s\n", key)
+	}
+	for _, key := range r.Skipped {
+		fmt.Fprintf(w, "  skip  %s (type not recoverable from constructor)\n", key)
+	}
+	for _, f := range r.Failures {
+		fmt.Fprintf(w, "  FAIL  %v\n", f)
+	}
+	fmt.Fprintf(w, "%d resolved, %d failed, %d skipped\n", len(r.Resolved), len(r.Failures), len(r.Skipped))
+}
+
+// Doctor resolves every type registered through the module system and
+// reports all failures at once, instead of stopping at the first one.
+// Resolving runs the constructors, so it should only be used at startup.
+func (c *Container) Doctor() *DoctorReport {
+	report := &DoctorReport{}
+	seen := make(map[string]bool)
+
+	for _, p := range c.moduleRegistry.GetProviders() {
+		for _, out := range p.Outputs {
+			if seen[out.Key] {
+				continue
+			}
+			seen[out.Key] = true
+
+			if out.Type == nil {
+				report.Skipped = append(report.Skipped, out.Key)
+				continue
+			}
+
+			var err error
+			switch {
+			case out.Group != "":
+				err = c.Invoke(paramFunc(reflect.SliceOf(out.Type), fmt.Sprintf(`group:%q`, out.Group), discard))
+			case out.Name != "":
+				err = c.Invoke(paramFunc(out.Type, fmt.Sprintf(`name:%q`, out.Name), discard))
+			default:
+				err = c.Invoke(paramFunc(out.Type, "", discard))
+			}
+
+			if err != nil {
+				failure := c.resolveError(out.Type, out.Name, out.Group, err)
+				failure.Module = p.Module
+				report.Failures = append(report.Failures, failure)
+				continue
+			}
+			report.Resolved = append(report.Resolved, out.Key)
+		}
+	}
+	return report
+}
+
+func discard(reflect.Value) {}
@@ -0,0 +1,186 @@
+package di
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+)
+
+// Graph is a snapshot of the providers registered in the container,
+// annotated with the module that registered each one
+type Graph struct {
+	Modules   []string        `json:"modules"`
+	Providers []GraphProvider `json:"providers"`
+	// Missing lists inputs that no registered provider produces
+	Missing []GraphNode `json:"missing,omitempty"`
+}
+
+// GraphProvider is a constructor node in the graph
+type GraphProvider struct {
+	Module      string      `json:"module"`
+	Constructor string      `json:"constructor"`
+	Inputs      []GraphNode `json:"inputs"`
+	Outputs     []GraphNode `json:"outputs"`
+}
+
+// GraphNode is a value node in the graph
+type GraphNode struct {
+	Type     string `json:"type"`
+	Name     string `json:"name,omitempty"`
+	Group    string `json:"group,omitempty"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Graph returns the dependency graph of every provider registered so far
+func (c *Container) Graph() *Graph {
+	graph := &Graph{
+		Modules:   []string{CoreModule},
+		Providers: make([]GraphProvider, 0),
+	}
+	for _, m := range c.moduleRegistry.GetModules() {
+		graph.Modules = append(graph.Modules, m.Name())
+	}
+
+	produced := make(map[string]bool)
+	for _, p := range c.moduleRegistry.GetProviders() {
+		gp := GraphProvider{
+			Module:      p.Module,
+			Constructor: p.Constructor,
+			Inputs:      toGraphNodes(p.Inputs),
+			Outputs:     toGraphNodes(p.Outputs),
+		}
+		for _, out := range gp.Outputs {
+			produced[out.id()] = true
+		}
+		graph.Providers = append(graph.Providers, gp)
+	}
+
+	seen := make(map[string]bool)
+	for _, p := range graph.Providers {
+		for _, in := range p.Inputs {
+			// Empty groups and optional values are valid
+			if in.Optional || in.Group != "" || produced[in.id()] || seen[in.id()] {
+				continue
+			}
+			seen[in.id()] = true
+			graph.Missing = append(graph.Missing, in)
+		}
+	}
+	return graph
+}
+
+// WriteJSON writes the graph as indented JSON
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
+
+// WriteDOT writes the graph in Graphviz DOT format. Providers are grouped
+// into one cluster per module and missing inputs are drawn in red.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("digraph {\n")
+	b.WriteString("\trankdir=RL;\n")
+	b.WriteString("\tnode [shape=box, fontname=\"Helvetica\"];\n")
+
+	byModule := make(map[string][]int)
+	for i, p := range g.Providers {
+		byModule[p.Module] = append(byModule[p.Module], i)
+	}
+	moduleNames := make([]string, 0, len(byModule))
+	for name := range byModule {
+		moduleNames = append(moduleNames, name)
+	}
+	sort.Strings(moduleNames)
+
+	for _, module := range moduleNames {
+		fmt.Fprintf(&b, "\tsubgraph %q {\n", "cluster_"+module)
+		fmt.Fprintf(&b, "\t\tlabel=%q;\n", "module: "+module)
+		for _, i := range byModule[module] {
+			p := g.Providers[i]
+			fmt.Fprintf(&b, "\t\t%q [label=%q, shape=plaintext];\n", providerID(i), shortName(p.Constructor))
+			for _, out := range p.Outputs {
+				fmt.Fprintf(&b, "\t\t%q [label=%q];\n", out.id(), out.label())
+				fmt.Fprintf(&b, "\t\t%q -> %q;\n", providerID(i), out.id())
+			}
+		}
+		b.WriteString("\t}\n")
+	}
+
+	for i, p := range g.Providers {
+		for _, in := range p.Inputs {
+			style := ""
+			if in.Optional {
+				style = " [style=dashed]"
+			}
+			fmt.Fprintf(&b, "\t%q -> %q%s;\n", in.id(), providerID(i), style)
+		}
+	}
+
+	for _, missing := range g.Missing {
+		fmt.Fprintf(&b, "\t%q [label=%q, color=red, fontcolor=red];\n", missing.id(), missing.label()+"\\n(missing)")
+	}
+
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func toGraphNodes(deps []modules.Dependency) []GraphNode {
+	nodes := make([]GraphNode, 0, len(deps))
+	for _, d := range deps {
+		node := GraphNode{
+			Type:     strings.SplitN(d.Key, "[", 2)[0],
+			Name:     d.Name,
+			Group:    d.Group,
+			Optional: d.Optional,
+		}
+		// Group inputs are slices; key them by element type like the outputs
+		if d.Group != "" && strings.HasPrefix(node.Type, "[]") {
+			node.Type = strings.TrimPrefix(node.Type, "[]")
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// id returns a node identifier shared by matching inputs and outputs
+func (n GraphNode) id() string {
+	switch {
+	case n.Name != "":
+		return n.Type + "[name=" + n.Name + "]"
+	case n.Group != "":
+		return n.Type + "[group=" + n.Group + "]"
+	default:
+		return n.Type
+	}
+}
+
+func (n GraphNode) label() string {
+	switch {
+	case n.Name != "":
+		return n.Type + "\\nname: " + n.Name
+	case n.Group != "":
+		return "[]" + n.Type + "\\ngroup: " + n.Group
+	default:
+		return n.Type
+	}
+}
+
+func providerID(i int) string {
+	return fmt.Sprintf("provider%d", i)
+}
+
+// shortName trims the import path from a constructor name
+func shortName(constructor string) string {
+	if i := strings.LastIndex(constructor, "/"); i >= 0 {
+		return constructor[i+1:]
+	}
+	return constructor
+}
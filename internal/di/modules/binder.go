@@ -0,0 +1,60 @@
+package modules
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+)
+
+// Binder is the view of the DI container handed to Module.Register.
+// It embeds *dig.Container, so modules call Provide exactly as before,
+// and records every provider against the module that registered it.
+type Binder struct {
+	*dig.Container
+	module   string
+	registry *Registry
+}
+
+// NewBinder creates a binder that records providers for module in registry
+func NewBinder(container *dig.Container, module string, registry *Registry) *Binder {
+	return &Binder{
+		Container: container,
+		module:    module,
+		registry:  registry,
+	}
+}
+
+// Module returns the name of the module this binder registers for
+func (b *Binder) Module() string {
+	return b.module
+}
+
+// Provide registers a constructor in the container and records its outputs
+func (b *Binder) Provide(constructor interface{}, opts ...dig.ProvideOption) error {
+	var info dig.ProvideInfo
+	opts = append(opts, dig.FillProvideInfo(&info))
+
+	if err := b.Container.Provide(constructor, opts...); err != nil {
+		return err
+	}
+
+	b.registry.record(newProvider(b.module, constructor, info))
+	return nil
+}
+
+// ProvideScoped registers a constructor that runs once per request, in the
+// request scope created by the request-scope middleware. It may depend on
+// singletons and on the values provided for the request.
+func (b *Binder) ProvideScoped(constructor interface{}) error {
+	if reflect.TypeOf(constructor) == nil || reflect.TypeOf(constructor).Kind() != reflect.Func {
+		return fmt.Errorf("scoped constructor must be a function, got %T", constructor)
+	}
+
+	b.registry.recordScoped(ScopedProvider{
+		Module:      b.module,
+		Constructor: constructor,
+		Outputs:     resultTypes(reflect.TypeOf(constructor)),
+	})
+	return nil
+}
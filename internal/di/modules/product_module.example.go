@@ -1,14 +1,14 @@
 package modules
 
-import (
-	"go.uber.org/dig"
-	"gorm.io/gorm"
-	// Note: Import these when you create them
-	// "github.com/miladev95/golang-project-structure/internal/handlers/http"
-	// "github.com/miladev95/golang-project-structure/internal/repositories"
-	// postgresrepo "github.com/miladev95/golang-project-structure/internal/repositories/postgres"
-	// "github.com/miladev95/golang-project-structure/internal/services"
-)
+// Note: Import these when you create them
+// import (
+// 	"gorm.io/gorm"
+//
+// 	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+// 	"github.com/miladev95/golang-project-structure/internal/repositories"
+// 	postgresrepo "github.com/miladev95/golang-project-structure/internal/repositories/postgres"
+// 	"github.com/miladev95/golang-project-structure/internal/services"
+// )
 
 // ProductModule represents the product domain module
 // EXAMPLE: This is how to add a new module. Copy this pattern and create:
@@ -29,7 +29,7 @@ import (
 // 	return "product"
 // }
 //
-// func (m *ProductModule) Register(container *dig.Container) error {
+// func (m *ProductModule) Register(container *Binder) error {
 // 	// Register repository
 // 	if err := container.Provide(func(db *gorm.DB) repositories.ProductRepository {
 // 		return postgresrepo.NewProductRepository(db)
@@ -0,0 +1,174 @@
+package modules
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.uber.org/dig"
+)
+
+// Provider describes a constructor registered through a Binder
+type Provider struct {
+	Module      string
+	Constructor string
+	Inputs      []Dependency
+	Outputs     []Dependency
+}
+
+// ScopedProvider is a constructor that runs once per request scope
+type ScopedProvider struct {
+	Module      string
+	Constructor interface{}
+	Outputs     []reflect.Type
+}
+
+// Dependency identifies a value in the container by type, name and group
+type Dependency struct {
+	// Type is nil when it could not be recovered from the constructor
+	// signature (for example when dig.As is used)
+	Type     reflect.Type
+	Key      string
+	Name     string
+	Group    string
+	Optional bool
+}
+
+// String returns the dependency in dig's notation, e.g. *gorm.DB[name = "replica"]
+func (d Dependency) String() string {
+	return d.Key
+}
+
+var (
+	inType        = reflect.TypeOf(dig.In{})
+	outType       = reflect.TypeOf(dig.Out{})
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	keyNameRegex  = regexp.MustCompile(`name = "([^"]*)"`)
+	keyGroupRegex = regexp.MustCompile(`group = "([^"]*)"`)
+)
+
+// newProvider builds a Provider from the info dig filled in for constructor
+func newProvider(module string, constructor interface{}, info dig.ProvideInfo) Provider {
+	ctorType := reflect.TypeOf(constructor)
+	params := paramTypes(ctorType)
+	results := resultTypes(ctorType)
+
+	provider := Provider{
+		Module:      module,
+		Constructor: funcName(constructor),
+		Inputs:      make([]Dependency, 0, len(info.Inputs)),
+		Outputs:     make([]Dependency, 0, len(info.Outputs)),
+	}
+	for _, in := range info.Inputs {
+		provider.Inputs = append(provider.Inputs, parseDependency(in.String(), params))
+	}
+	for _, out := range info.Outputs {
+		provider.Outputs = append(provider.Outputs, parseDependency(out.String(), results))
+	}
+	return provider
+}
+
+// parseDependency parses dig's string form of a key and matches it to a type
+func parseDependency(key string, candidates []reflect.Type) Dependency {
+	dep := Dependency{Key: key}
+
+	typeName := key
+	if i := strings.Index(key, "["); i > 0 && strings.HasSuffix(key, "]") {
+		typeName = key[:i]
+	}
+	if m := keyNameRegex.FindStringSubmatch(key); m != nil {
+		dep.Name = m[1]
+	}
+	if m := keyGroupRegex.FindStringSubmatch(key); m != nil {
+		dep.Group = m[1]
+	}
+	dep.Optional = typeName != key && strings.Contains(key[len(typeName):], "optional")
+
+	for _, t := range candidates {
+		if t.String() == typeName {
+			dep.Type = t
+			break
+		}
+	}
+	return dep
+}
+
+// paramTypes returns the types a constructor depends on, expanding dig.In structs
+func paramTypes(fn reflect.Type) []reflect.Type {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return nil
+	}
+
+	var types []reflect.Type
+	for i := 0; i < fn.NumIn(); i++ {
+		types = appendStructFields(types, fn.In(i), inType)
+	}
+	return types
+}
+
+// ResultTypes returns the types a constructor produces, expanding dig.Out structs
+func ResultTypes(constructor interface{}) []reflect.Type {
+	return resultTypes(reflect.TypeOf(constructor))
+}
+
+// resultTypes returns the types a constructor produces, expanding dig.Out structs
+func resultTypes(fn reflect.Type) []reflect.Type {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return nil
+	}
+
+	var types []reflect.Type
+	for i := 0; i < fn.NumOut(); i++ {
+		if fn.Out(i) != errorType {
+			types = appendStructFields(types, fn.Out(i), outType)
+		}
+	}
+	return types
+}
+
+// appendStructFields appends t, or the fields of t when it embeds marker
+// (dig.In or dig.Out)
+func appendStructFields(types []reflect.Type, t, marker reflect.Type) []reflect.Type {
+	if !embeds(t, marker) {
+		return append(types, t)
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type == marker || field.PkgPath != "" {
+			continue
+		}
+		types = appendStructFields(types, field.Type, marker)
+		// Grouped values are keyed by the slice element type
+		if field.Type.Kind() == reflect.Slice {
+			types = append(types, field.Type.Elem())
+		}
+	}
+	return types
+}
+
+func embeds(t, marker reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == marker {
+			return true
+		}
+	}
+	return false
+}
+
+// funcName returns the fully qualified name of a function value
+func funcName(fn interface{}) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fmt.Sprintf("%T", fn)
+	}
+	if f := runtime.FuncForPC(v.Pointer()); f != nil {
+		return f.Name()
+	}
+	return v.Type().String()
+}
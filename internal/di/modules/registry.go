@@ -1,16 +1,24 @@
 package modules
 
-import "go.uber.org/dig"
+import (
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+)
 
 // Registry manages module registration
 type Registry struct {
-	modules []Module
+	modules   []Module
+	providers []Provider
+	scoped    []ScopedProvider
 }
 
 // NewRegistry creates a new module registry
 func NewRegistry() *Registry {
 	return &Registry{
-		modules: make([]Module, 0),
+		modules:   make([]Module, 0),
+		providers: make([]Provider, 0),
 	}
 }
 
@@ -23,8 +31,8 @@ func (r *Registry) Register(module Module) *Registry {
 // Setup registers all modules in the container
 func (r *Registry) Setup(container *dig.Container) error {
 	for _, module := range r.modules {
-		if err := module.Register(container); err != nil {
-			return err
+		if err := module.Register(NewBinder(container, module.Name(), r)); err != nil {
+			return fmt.Errorf("failed to register module %q: %w", module.Name(), err)
 		}
 	}
 	return nil
@@ -33,4 +41,48 @@ func (r *Registry) Setup(container *dig.Container) error {
 // GetModules returns all registered modules
 func (r *Registry) GetModules() []Module {
 	return r.modules
-}
\ No newline at end of file
+}
+
+// GetProviders returns every provider registered through a Binder
+func (r *Registry) GetProviders() []Provider {
+	return r.providers
+}
+
+// GetScopedProviders returns every request-scoped provider
+func (r *Registry) GetScopedProviders() []ScopedProvider {
+	return r.scoped
+}
+
+// FindProvider returns the provider of the value with the given type and name
+func (r *Registry) FindProvider(t reflect.Type, name string) (Provider, bool) {
+	for _, p := range r.providers {
+		for _, out := range p.Outputs {
+			if out.Type == t && out.Name == name && out.Group == "" {
+				return p, true
+			}
+		}
+	}
+	return Provider{}, false
+}
+
+// FindGroupProviders returns every provider contributing to a value group
+func (r *Registry) FindGroupProviders(t reflect.Type, group string) []Provider {
+	var providers []Provider
+	for _, p := range r.providers {
+		for _, out := range p.Outputs {
+			if out.Type == t && out.Group == group {
+				providers = append(providers, p)
+				break
+			}
+		}
+	}
+	return providers
+}
+
+func (r *Registry) record(p Provider) {
+	r.providers = append(r.providers, p)
+}
+
+func (r *Registry) recordScoped(p ScopedProvider) {
+	r.scoped = append(r.scoped, p)
+}
@@ -1,13 +1,14 @@
 package modules
 
 import (
-	"go.uber.org/dig"
 	"gorm.io/gorm"
 
+	"github.com/miladev95/golang-project-structure/internal/events"
 	"github.com/miladev95/golang-project-structure/internal/handlers/http"
 	"github.com/miladev95/golang-project-structure/internal/repositories"
 	postgresrepo "github.com/miladev95/golang-project-structure/internal/repositories/postgres"
 	"github.com/miladev95/golang-project-structure/internal/services"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
 )
 
 // UserModule represents the user domain module
@@ -24,7 +25,7 @@ func (m *UserModule) Name() string {
 }
 
 // Register registers user module dependencies
-func (m *UserModule) Register(container *dig.Container) error {
+func (m *UserModule) Register(container *Binder) error {
 	// Register repository
 	if err := container.Provide(func(db *gorm.DB) repositories.UserRepository {
 		return postgresrepo.NewUserRepository(db)
@@ -33,8 +34,8 @@ func (m *UserModule) Register(container *dig.Container) error {
 	}
 
 	// Register service
-	if err := container.Provide(func(userRepo repositories.UserRepository) services.UserService {
-		return services.NewUserService(userRepo)
+	if err := container.Provide(func(userRepo repositories.UserRepository, tx transaction.Manager, bus *events.Bus) services.UserService {
+		return services.NewUserService(userRepo, tx, bus)
 	}); err != nil {
 		return err
 	}
@@ -0,0 +1,42 @@
+package di
+
+import (
+	"fmt"
+)
+
+// Override registers a replacement constructor that is applied with dig's
+// Decorate once Setup has registered every module. It is meant for tests:
+// register the real modules, then swap out the types that touch the outside
+// world.
+//
+// A constructor that does not take the overridden type as a parameter
+// replaces it outright, so the original provider is never called:
+//
+//	container.Override(func() repositories.UserRepository { return fakeRepo })
+//
+// One that does take it wraps the original value instead:
+//
+//	container.Override(func(repo repositories.UserRepository) repositories.UserRepository {
+//		return &countingRepository{next: repo}
+//	})
+func (c *Container) Override(constructor interface{}) *Container {
+	c.overrides = append(c.overrides, constructor)
+	return c
+}
+
+// Replace overrides the value of type T with value
+//
+//	di.Replace[*gorm.DB](container, testDB)
+func Replace[T any](c *Container, value T) *Container {
+	return c.Override(func() T { return value })
+}
+
+// applyOverrides decorates the container with every registered override
+func (c *Container) applyOverrides() error {
+	for _, override := range c.overrides {
+		if err := c.Decorate(override); err != nil {
+			return fmt.Errorf("failed to apply override %T: %w", override, err)
+		}
+	}
+	return nil
+}
@@ -1,19 +1,225 @@
 package di
 
 import (
+	"time"
+
+	"go.uber.org/dig"
 	"gorm.io/gorm"
 
 	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/events"
+	"github.com/miladev95/golang-project-structure/internal/health"
+	"github.com/miladev95/golang-project-structure/internal/jobs"
+	"github.com/miladev95/golang-project-structure/internal/messaging"
+	"github.com/miladev95/golang-project-structure/internal/outbox"
+	"github.com/miladev95/golang-project-structure/internal/scheduler"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
 )
 
 // ProvideConfig provides the application configuration
 func (c *Container) ProvideConfig(cfg *config.Config) error {
-	return c.Provide(func() *config.Config { return cfg })
+	return c.core().Provide(func() *config.Config { return cfg })
 }
 
-// ProvideDatabase provides the database connection
+// ProvideDatabase provides the database connection and the transaction
+// manager on it
 func (c *Container) ProvideDatabase(cfg *config.Config) error {
-	return c.Provide(func() (*gorm.DB, error) {
+	if err := c.core().Provide(func() (*gorm.DB, error) {
 		return config.NewDatabase(cfg)
+	}); err != nil {
+		return err
+	}
+
+	return c.core().Provide(transaction.NewManager)
+}
+
+// healthChecks collects the checks modules provide into health.Group
+type healthChecks struct {
+	dig.In
+
+	Registrations []health.Registration `group:"health.checks"`
+}
+
+// ProvideHealth provides the health registry and the core database checks
+func (c *Container) ProvideHealth(cfg *config.Config) error {
+	core := c.core()
+
+	if err := core.Provide(func(db *gorm.DB) health.Registration {
+		return health.Registration{Kind: health.Readiness, Checker: health.NewDatabaseCheck(db)}
+	}, dig.Group(health.Group)); err != nil {
+		return err
+	}
+
+	if err := core.Provide(func(db *gorm.DB) health.Registration {
+		return health.Registration{Kind: health.Readiness, Checker: health.NewMigrationCheck(db)}
+	}, dig.Group(health.Group)); err != nil {
+		return err
+	}
+
+	return core.Provide(func(checks healthChecks) *health.Registry {
+		registry := health.NewRegistry(cfg.Health.Timeout, cfg.Health.CacheTTL)
+		for _, r := range checks.Registrations {
+			registry.Register(r.Kind, r.Checker)
+		}
+		return registry
+	})
+}
+
+// jobHandlers collects the handlers modules provide into jobs.Group
+type jobHandlers struct {
+	dig.In
+
+	Registrations []jobs.Registration `group:"jobs.handlers"`
+}
+
+// ProvideJobs provides the job store, the queue modules enqueue into and
+// the worker that runs registered handlers
+func (c *Container) ProvideJobs(cfg *config.Config) error {
+	core := c.core()
+
+	if err := core.Provide(jobs.NewStore); err != nil {
+		return err
+	}
+
+	if err := core.Provide(func(store jobs.Store) jobs.Queue {
+		return jobs.NewQueue(store, cfg.Jobs.MaxAttempts)
+	}); err != nil {
+		return err
+	}
+
+	return core.Provide(func(store jobs.Store, handlers jobHandlers) (*jobs.Worker, error) {
+		return jobs.NewWorker(store, handlers.Registrations, jobs.Options{
+			Concurrency:  cfg.Jobs.Workers,
+			PollInterval: cfg.Jobs.PollInterval,
+			LockTimeout:  cfg.Jobs.LockTimeout,
+		})
+	})
+}
+
+// scheduledTasks collects the tasks modules provide into scheduler.Group
+type scheduledTasks struct {
+	dig.In
+
+	Registrations []scheduler.Registration `group:"scheduler.tasks"`
+}
+
+// ProvideScheduler provides the scheduler that runs registered cron tasks
+func (c *Container) ProvideScheduler(cfg *config.Config) error {
+	core := c.core()
+
+	if err := core.Provide(scheduler.NewStore); err != nil {
+		return err
+	}
+
+	return core.Provide(func(store scheduler.Store, tasks scheduledTasks) (*scheduler.Scheduler, error) {
+		location, err := time.LoadLocation(cfg.Scheduler.Timezone)
+		if err != nil {
+			return nil, err
+		}
+		return scheduler.New(store, tasks.Registrations, scheduler.Options{
+			LeaseTTL: cfg.Scheduler.LeaseTTL,
+			Location: location,
+		})
+	})
+}
+
+// ProvideOutbox provides the outbox relay and the publisher selected by
+// configuration
+func (c *Container) ProvideOutbox(cfg *config.Config) error {
+	core := c.core()
+
+	if err := core.Provide(outbox.NewStore); err != nil {
+		return err
+	}
+
+	// Only the broker publisher depends on the broker, so the log and
+	// webhook publishers never connect to NATS
+	var publisher interface{}
+	switch cfg.Outbox.Publisher {
+	case "webhook":
+		publisher = func() outbox.Publisher {
+			return outbox.NewWebhookPublisher(cfg.Outbox.WebhookURL, cfg.Outbox.WebhookSecret, nil)
+		}
+	case "broker":
+		publisher = func(broker messaging.Broker) outbox.Publisher {
+			return messaging.NewOutboxPublisher(broker)
+		}
+	default:
+		publisher = func() outbox.Publisher {
+			return outbox.NewLogPublisher(nil)
+		}
+	}
+	if err := core.Provide(publisher); err != nil {
+		return err
+	}
+
+	return core.Provide(func(store outbox.Store, publisher outbox.Publisher) *outbox.Relay {
+		return outbox.NewRelay(store, publisher, outbox.Options{
+			BatchSize:    cfg.Outbox.BatchSize,
+			Lease:        cfg.Outbox.LeaseTTL,
+			PollInterval: cfg.Outbox.PollInterval,
+		})
+	})
+}
+
+// messagingConsumers collects the consumers modules provide into messaging.Group
+type messagingConsumers struct {
+	dig.In
+
+	Registrations []messaging.Registration `group:"messaging.consumers"`
+}
+
+// ProvideMessaging provides the message broker selected by configuration,
+// the processed events store and the registered consumers
+func (c *Container) ProvideMessaging(cfg *config.Config) error {
+	core := c.core()
+
+	if err := core.Provide(func() (messaging.Broker, error) {
+		if cfg.Messaging.Driver == "nats" {
+			return messaging.NewNATSBroker(messaging.NATSOptions{
+				URL:        cfg.Messaging.NATSURL,
+				Stream:     cfg.Messaging.NATSStream,
+				Subjects:   cfg.Messaging.NATSSubjects,
+				AckWait:    cfg.Messaging.AckWait,
+				MaxDeliver: cfg.Messaging.MaxDeliver,
+			})
+		}
+		broker := messaging.NewMemoryBroker()
+		if cfg.Messaging.MaxDeliver > 0 {
+			broker.MaxDeliver = cfg.Messaging.MaxDeliver
+		}
+		return broker, nil
+	}); err != nil {
+		return err
+	}
+
+	if err := core.Provide(messaging.NewProcessedStore); err != nil {
+		return err
+	}
+
+	return core.Provide(func(broker messaging.Broker, tx transaction.Manager, store messaging.ProcessedStore, consumers messagingConsumers) (*messaging.Consumers, error) {
+		return messaging.NewConsumers(broker, tx, store, consumers.Registrations)
+	})
+}
+
+// eventSubscribers collects the subscriptions modules provide into events.Group
+type eventSubscribers struct {
+	dig.In
+
+	Registrations []events.Registration `group:"events.subscribers"`
+}
+
+// ProvideEvents provides the domain event bus with every module
+// subscription applied
+func (c *Container) ProvideEvents(cfg *config.Config) error {
+	return c.core().Provide(func(subscribers eventSubscribers) *events.Bus {
+		bus := events.NewBus(events.Options{
+			Shards:     cfg.Events.AsyncWorkers,
+			BufferSize: cfg.Events.BufferSize,
+		})
+		for _, r := range subscribers.Registrations {
+			r.Subscribe(bus)
+		}
+		return bus
 	})
 }
@@ -0,0 +1,303 @@
+package di
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/dig"
+
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+)
+
+// RequestScope holds the dependencies of a single request. It embeds a
+// *Container, so the Resolve helpers work on it the same way they work on
+// the root container.
+//
+// A child created with dig's Scope method stays attached to its parent for
+// the life of the process, which would leak one scope per request. Instead
+// every request scope is a fresh dig container holding the request values,
+// the request-scoped providers and the root's overrides of them. Singletons
+// are bridged from the root container only when something in the scope
+// needs them, so the cost of a scope doesn't grow with the root graph.
+// Nothing references the scope once the request ends.
+type RequestScope struct {
+	*Container
+
+	mu       sync.Mutex
+	cleanups []func()
+	disposed bool
+}
+
+// Disposer lets request-scoped constructors register cleanup functions that
+// run when the request ends, e.g. rolling back an unfinished transaction
+type Disposer interface {
+	OnDispose(fn func())
+}
+
+// scopeKey identifies a value in a request scope by type and name
+type scopeKey struct {
+	t    reflect.Type
+	name string
+}
+
+// bridge provides one root singleton into request scopes
+type bridge struct {
+	key         scopeKey
+	constructor interface{}
+	opts        []dig.ProvideOption
+}
+
+// bridgedValue is a singleton resolved from the root container
+type bridgedValue struct {
+	value reflect.Value
+}
+
+// NewRequestScope creates the scope for one request. providers are
+// constructors for request values such as the request context, e.g.
+//
+//	func() context.Context { return c.Request.Context() }
+//
+// Types returned by providers or by request-scoped module providers shadow
+// singletons of the same type.
+func (c *Container) NewRequestScope(providers ...interface{}) (*RequestScope, error) {
+	root := c.root()
+	scope := &RequestScope{
+		Container: &Container{
+			// The module providers were checked for cycles in the root
+			// container already
+			Container:      dig.New(dig.DeferAcyclicVerification()),
+			moduleRegistry: root.moduleRegistry,
+			parent:         root,
+			scopeKeys:      make(map[scopeKey]bool),
+		},
+	}
+	local := scope.Container
+
+	var params []reflect.Type
+	provide := func(constructor interface{}) error {
+		for _, key := range resultKeys(reflect.TypeOf(constructor)) {
+			local.scopeKeys[key] = true
+		}
+		params = append(params, reflect.TypeOf(constructor))
+		return local.Container.Provide(constructor)
+	}
+
+	if err := provide(func() Disposer { return scope }); err != nil {
+		return nil, err
+	}
+	for _, p := range providers {
+		if err := provide(p); err != nil {
+			return nil, fmt.Errorf("failed to provide request value %T: %w", p, err)
+		}
+	}
+	for _, p := range root.moduleRegistry.GetScopedProviders() {
+		if err := provide(p.Constructor); err != nil {
+			return nil, fmt.Errorf("failed to provide request-scoped %T from module %q: %w", p.Constructor, p.Module, err)
+		}
+	}
+
+	// Overrides of request-scoped types apply in every scope; the root
+	// already applied the others to the singletons bridged below
+	for _, override := range root.overrides {
+		if !local.providesAll(resultKeys(reflect.TypeOf(override))) {
+			continue
+		}
+		if err := local.Container.Decorate(override); err != nil {
+			return nil, fmt.Errorf("failed to apply override %T in request scope: %w", override, err)
+		}
+		params = append(params, reflect.TypeOf(override))
+	}
+
+	for _, fn := range params {
+		if err := local.bridgeParams(fn); err != nil {
+			return nil, err
+		}
+	}
+	return scope, nil
+}
+
+// OnDispose registers fn to run when the request ends
+func (s *RequestScope) OnDispose(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cleanups = append(s.cleanups, fn)
+}
+
+// Dispose runs the cleanup functions in reverse order of registration.
+// It is safe to call more than once.
+func (s *RequestScope) Dispose() {
+	s.mu.Lock()
+	if s.disposed {
+		s.mu.Unlock()
+		return
+	}
+	s.disposed = true
+	cleanups := s.cleanups
+	s.cleanups = nil
+	s.mu.Unlock()
+
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+}
+
+// root returns the root container of a request scope, or c itself
+func (c *Container) root() *Container {
+	if c.parent != nil {
+		return c.parent
+	}
+	return c
+}
+
+// Invoke calls function with its parameters resolved from the container.
+// In a request scope, singletons it needs are bridged from the root first.
+func (c *Container) Invoke(function interface{}, opts ...dig.InvokeOption) error {
+	if c.parent != nil {
+		if err := c.bridgeParams(reflect.TypeOf(function)); err != nil {
+			return err
+		}
+	}
+	return c.Container.Invoke(function, opts...)
+}
+
+// bridgeParams provides into a request scope a bridge for every parameter
+// of fn that the scope lacks and the root provides
+func (c *Container) bridgeParams(fn reflect.Type) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	bridges := c.parent.bridges()
+	for _, key := range paramKeys(fn) {
+		if c.scopeKeys[key] {
+			continue
+		}
+		b, ok := bridges[key]
+		if !ok {
+			continue
+		}
+		if err := c.Container.Provide(b.constructor, b.opts...); err != nil {
+			return fmt.Errorf("failed to bridge %v into request scope: %w", key.t, err)
+		}
+		c.scopeKeys[key] = true
+	}
+	return nil
+}
+
+// providesAll reports whether a request scope provides every key itself
+func (c *Container) providesAll(keys []scopeKey) bool {
+	for _, key := range keys {
+		if !c.scopeKeys[key] {
+			return false
+		}
+	}
+	return len(keys) > 0
+}
+
+// bridges builds, once, a constructor for every singleton that resolves it
+// from the root container. dig containers are not safe for concurrent use,
+// so the first resolution of each singleton is serialized on the root;
+// later requests reuse the resolved value without locking.
+func (c *Container) bridges() map[scopeKey]bridge {
+	c.bridgeOnce.Do(func() {
+		c.bridgeIndex = make(map[scopeKey]bridge)
+		for _, p := range c.moduleRegistry.GetProviders() {
+			for _, out := range p.Outputs {
+				// Groups are left out: a group can't be shadowed per request
+				if out.Type == nil || out.Group != "" {
+					continue
+				}
+				b := c.newBridge(out)
+				c.bridgeIndex[b.key] = b
+			}
+		}
+	})
+	return c.bridgeIndex
+}
+func (c *Container) newBridge(out modules.Dependency) bridge {
+	tag := ""
+	var opts []dig.ProvideOption
+	if out.Name != "" {
+		tag = fmt.Sprintf(`name:%q`, out.Name)
+		opts = append(opts, dig.Name(out.Name))
+	}
+
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	fnType := reflect.FuncOf(nil, []reflect.Type{out.Type, errorType}, false)
+	var resolved atomic.Pointer[bridgedValue]
+	fn := reflect.MakeFunc(fnType, func([]reflect.Value) []reflect.Value {
+		if v := resolved.Load(); v != nil {
+			return []reflect.Value{v.value, reflect.Zero(errorType)}
+		}
+
+		var value reflect.Value
+		set := func(v reflect.Value) { value = v }
+
+		c.mu.Lock()
+		err := c.Invoke(paramFunc(out.Type, tag, set))
+		c.mu.Unlock()
+
+		// Failures aren't cached so a later request can retry
+		if err != nil {
+			return []reflect.Value{reflect.Zero(out.Type), reflect.ValueOf(&err).Elem()}
+		}
+		resolved.Store(&bridgedValue{value: value})
+		return []reflect.Value{value, reflect.Zero(errorType)}
+	})
+
+	return bridge{key: scopeKey{t: out.Type, name: out.Name}, constructor: fn.Interface(), opts: opts}
+}
+
+// paramKeys returns the values fn takes, expanding dig.In structs. Grouped
+// fields are left out.
+func paramKeys(fn reflect.Type) []scopeKey {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return nil
+	}
+	var keys []scopeKey
+	for i := 0; i < fn.NumIn(); i++ {
+		keys = appendFieldKeys(keys, fn.In(i), reflect.TypeOf(dig.In{}), "")
+	}
+	return keys
+}
+
+// resultKeys returns the values fn produces, expanding dig.Out structs
+func resultKeys(fn reflect.Type) []scopeKey {
+	if fn == nil || fn.Kind() != reflect.Func {
+		return nil
+	}
+	errorType := reflect.TypeOf((*error)(nil)).Elem()
+	var keys []scopeKey
+	for i := 0; i < fn.NumOut(); i++ {
+		if fn.Out(i) != errorType {
+			keys = appendFieldKeys(keys, fn.Out(i), reflect.TypeOf(dig.Out{}), "")
+		}
+	}
+	return keys
+}
+
+// appendFieldKeys appends the key of t, or of the fields of t when it
+// embeds marker (dig.In or dig.Out)
+func appendFieldKeys(keys []scopeKey, t, marker reflect.Type, name string) []scopeKey {
+	if t.Kind() != reflect.Struct || !embedsField(t, marker) {
+		return append(keys, scopeKey{t: t, name: name})
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Type == marker || field.PkgPath != "" || field.Tag.Get("group") != "" {
+			continue
+		}
+		keys = appendFieldKeys(keys, field.Type, marker, field.Tag.Get("name"))
+	}
+	return keys
+}
+
+func embedsField(t, marker reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.Anonymous && f.Type == marker {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package di
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go.uber.org/dig"
+
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+)
+
+// ResolveError is returned when a value cannot be resolved from the container.
+// It names the requested type and the module expected to provide it, and,
+// when the failure is in a transitive dependency, that dependency and its
+// module.
+type ResolveError struct {
+	Type   reflect.Type
+	Name   string
+	Group  string
+	Module string
+	// Dependency is the transitive dependency that is missing or whose
+	// constructor failed, in dig's notation; empty when Type itself failed
+	Dependency string
+	// DependencyModule is the module providing Dependency; empty when no
+	// registered module does
+	DependencyModule string
+	// Modules lists the registered modules, used when no module provides
+	// Type or Dependency
+	Modules []string
+	Err     error
+}
+
+func (e *ResolveError) Error() string {
+	key := e.key()
+	msg := fmt.Sprintf("di: failed to resolve %s provided by module %q", key, e.Module)
+	if e.Module == "" {
+		msg = fmt.Sprintf("di: no registered module provides %s (registered: %s)", key, strings.Join(e.Modules, ", "))
+	}
+
+	switch {
+	case e.Dependency == "":
+	case e.DependencyModule == "":
+		msg += fmt.Sprintf(": no registered module provides dependency %s (registered: %s)",
+			e.Dependency, strings.Join(e.Modules, ", "))
+	default:
+		msg += fmt.Sprintf(": dependency %s provided by module %q failed", e.Dependency, e.DependencyModule)
+	}
+	return fmt.Sprintf("%s: %v", msg, dig.RootCause(e.Err))
+}
+
+func (e *ResolveError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ResolveError) key() string {
+	switch {
+	case e.Name != "":
+		return fmt.Sprintf("%v[name = %q]", e.Type, e.Name)
+	case e.Group != "":
+		return fmt.Sprintf("%v[group = %q]", e.Type, e.Group)
+	default:
+		return fmt.Sprint(e.Type)
+	}
+}
+
+// Resolve returns the value of type T from the container
+//
+//	userHandler, err := di.Resolve[*http.UserHandler](container)
+func Resolve[T any](c *Container) (T, error) {
+	var value T
+	if err := c.Invoke(func(v T) { value = v }); err != nil {
+		return value, c.resolveError(typeOf[T](), "", "", err)
+	}
+	return value, nil
+}
+
+// MustResolve is like Resolve but panics if the value cannot be resolved.
+// Use it during startup, where a missing dependency is a programming error.
+func MustResolve[T any](c *Container) T {
+	value, err := Resolve[T](c)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// ResolveNamed returns the value of type T provided with dig.Name(name)
+func ResolveNamed[T any](c *Container, name string) (T, error) {
+	var value T
+	fn := paramFunc(typeOf[T](), fmt.Sprintf(`name:%q`, name), func(v reflect.Value) {
+		value = v.Interface().(T)
+	})
+	if err := c.Invoke(fn); err != nil {
+		return value, c.resolveError(typeOf[T](), name, "", err)
+	}
+	return value, nil
+}
+
+// ResolveGroup returns every value of type T provided with dig.Group(group)
+func ResolveGroup[T any](c *Container, group string) ([]T, error) {
+	var values []T
+	fn := paramFunc(reflect.TypeOf(values), fmt.Sprintf(`group:%q`, group), func(v reflect.Value) {
+		values = v.Interface().([]T)
+	})
+	if err := c.Invoke(fn); err != nil {
+		return nil, c.resolveError(typeOf[T](), "", group, err)
+	}
+	return values, nil
+}
+
+// resolveError wraps a dig error with the module expected to provide the
+// type, and the transitive dependency that failed with its module
+func (c *Container) resolveError(t reflect.Type, name, group string, err error) *ResolveError {
+	resolveErr := &ResolveError{Type: t, Name: name, Group: group, Err: err}
+
+	if group != "" {
+		if providers := c.moduleRegistry.FindGroupProviders(t, group); len(providers) > 0 {
+			resolveErr.Module = providers[0].Module
+		}
+	} else if provider, ok := c.moduleRegistry.FindProvider(t, name); ok {
+		resolveErr.Module = provider.Module
+	}
+
+	// Point at the transitive dependency that failed, if it isn't t itself
+	if missing, ok := missingType(err); ok && missing != digKey(t, name) {
+		resolveErr.Dependency = missing
+		if provider, ok := c.findProviderOf(missing); ok {
+			resolveErr.DependencyModule = provider.Module
+		}
+	} else if provider, ok := c.findConstructor(failedConstructor(err)); ok && !provides(provider, t, name, group) {
+		resolveErr.Dependency = provider.Outputs[0].Key
+		resolveErr.DependencyModule = provider.Module
+	}
+
+	if resolveErr.Module == "" || (resolveErr.Dependency != "" && resolveErr.DependencyModule == "") {
+		resolveErr.Modules = []string{CoreModule}
+		for _, m := range c.moduleRegistry.GetModules() {
+			resolveErr.Modules = append(resolveErr.Modules, m.Name())
+		}
+	}
+	return resolveErr
+}
+
+// missingType returns the first type dig reports missing at the root of
+// err, e.g. "*gorm.DB"
+func missingType(err error) (string, bool) {
+	const prefix = "missing type: "
+	msg := dig.RootCause(err).Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return "", false
+	}
+	missing := strings.TrimPrefix(msg, prefix)
+	if i := strings.IndexAny(missing, " ;"); i >= 0 {
+		missing = missing[:i]
+	}
+	return missing, true
+}
+
+// failedConstructor returns the fully qualified name of the innermost
+// constructor in err's chain. dig's error types are unexported, so their
+// Func field is read by reflection.
+func failedConstructor(err error) string {
+	var name string
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		fn := v.FieldByName("Func")
+		if !fn.IsValid() || fn.Kind() != reflect.Ptr || fn.IsNil() {
+			continue
+		}
+		pkg, fnName := fn.Elem().FieldByName("Package"), fn.Elem().FieldByName("Name")
+		if pkg.Kind() == reflect.String && fnName.Kind() == reflect.String {
+			name = pkg.String() + "." + fnName.String()
+		}
+	}
+	return name
+}
+
+// digKey formats an ungrouped key the way dig's errors do
+func digKey(t reflect.Type, name string) string {
+	if name != "" {
+		return fmt.Sprintf("%v[name=%q]", t, name)
+	}
+	return t.String()
+}
+
+// findProviderOf returns the provider of the ungrouped value with dig key
+func (c *Container) findProviderOf(key string) (modules.Provider, bool) {
+	typeName, name := key, ""
+	if i := strings.Index(key, "[name="); i > 0 {
+		typeName, name = key[:i], strings.Trim(key[i+len("[name="):len(key)-1], `"`)
+	}
+	for _, p := range c.moduleRegistry.GetProviders() {
+		for _, out := range p.Outputs {
+			if out.Type != nil && out.Type.String() == typeName && out.Name == name && out.Group == "" {
+				return p, true
+			}
+		}
+	}
+	return modules.Provider{}, false
+}
+
+// provides reports whether p provides the value identified by t, name and
+// group. A provider without outputs is treated as providing it.
+func provides(p modules.Provider, t reflect.Type, name, group string) bool {
+	for _, out := range p.Outputs {
+		if out.Type == t && out.Name == name && out.Group == group {
+			return true
+		}
+	}
+	return len(p.Outputs) == 0
+}
+
+// findConstructor returns the provider registered with constructor
+func (c *Container) findConstructor(constructor string) (modules.Provider, bool) {
+	if constructor == "" {
+		return modules.Provider{}, false
+	}
+	for _, p := range c.moduleRegistry.GetProviders() {
+		if p.Constructor == constructor {
+			return p, true
+		}
+	}
+	return modules.Provider{}, false
+}
+
+// paramFunc builds func(struct{ dig.In; Value T `tag` }) that passes Value to set
+func paramFunc(t reflect.Type, tag string, set func(reflect.Value)) interface{} {
+	param := reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: reflect.TypeOf(dig.In{}), Anonymous: true},
+		{Name: "Value", Type: t, Tag: reflect.StructTag(tag)},
+	})
+	fnType := reflect.FuncOf([]reflect.Type{param}, nil, false)
+	return reflect.MakeFunc(fnType, func(args []reflect.Value) []reflect.Value {
+		set(args[0].Field(1))
+		return nil
+	}).Interface()
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
@@ -0,0 +1,52 @@
+// Package scope carries a DI request scope through context.Context.
+//
+// It only depends on dig, so handlers and services can resolve
+// request-scoped values without importing the di package, which would
+// create an import cycle through the modules.
+package scope
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"go.uber.org/dig"
+)
+
+// ErrNoScope is returned when the context carries no request scope
+var ErrNoScope = errors.New("no request scope in context")
+
+// Scope is the part of a request scope needed to resolve values.
+// *di.RequestScope implements it.
+type Scope interface {
+	Invoke(function interface{}, opts ...dig.InvokeOption) error
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries s
+func NewContext(ctx context.Context, s Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the request scope carried by ctx, if any
+func FromContext(ctx context.Context) (Scope, bool) {
+	s, ok := ctx.Value(contextKey{}).(Scope)
+	return s, ok
+}
+
+// Resolve returns the value of type T from the request scope in ctx
+//
+//	logger, err := scope.Resolve[*log.Logger](c.Request.Context())
+func Resolve[T any](ctx context.Context) (T, error) {
+	var value T
+	s, ok := FromContext(ctx)
+	if !ok {
+		return value, ErrNoScope
+	}
+	if err := s.Invoke(func(v T) { value = v }); err != nil {
+		return value, fmt.Errorf("scope: failed to resolve %v: %w", reflect.TypeOf((*T)(nil)).Elem(), dig.RootCause(err))
+	}
+	return value, nil
+}
@@ -0,0 +1,242 @@
+// Package events is an in-process, typed domain event bus. Synchronous
+// subscribers run in the publisher's goroutine; asynchronous subscribers run
+// on a fixed set of shards, so events of one aggregate are delivered in the
+// order they were published.
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"reflect"
+	"sync"
+)
+
+// Group is the dig value group modules provide Registrations into
+const Group = "events.subscribers"
+
+// ErrClosed is returned when publishing to a closed bus
+var ErrClosed = errors.New("event bus is closed")
+
+// Event is a domain event
+type Event interface {
+	// EventName identifies the event in logs, e.g. "user.created"
+	EventName() string
+	// AggregateID is the ID of the entity the event is about; async
+	// delivery is ordered per aggregate
+	AggregateID() string
+}
+
+// Registration is what a module provides to subscribe to events:
+//
+//	container.Provide(func(mailer Mailer) events.Registration {
+//		return events.Registration{Subscribe: func(bus *events.Bus) {
+//			events.Subscribe(bus, mailer.OnUserCreated, events.Async())
+//		}}
+//	}, dig.Group(events.Group))
+type Registration struct {
+	Subscribe func(bus *Bus)
+}
+
+// Options configures a Bus
+type Options struct {
+	// Shards is the number of goroutines running async subscribers
+	Shards int
+	// BufferSize is the number of events each shard queues before
+	// Publish blocks
+	BufferSize int
+}
+
+// handler is a subscriber with its type information erased
+type handler struct {
+	name  string
+	async bool
+	fn    func(ctx context.Context, event Event) error
+}
+
+// delivery is an event queued for async subscribers
+type delivery struct {
+	ctx      context.Context
+	event    Event
+	handlers []handler
+}
+
+// Bus dispatches events to subscribers by event type
+type Bus struct {
+	mu       sync.RWMutex
+	handlers map[reflect.Type][]handler
+	closed   bool
+	// done is closed by Close to release publishers blocked on a full shard
+	done chan struct{}
+	// sending counts enqueues in flight; shards close once it drops to zero
+	sending sync.WaitGroup
+
+	shards []chan delivery
+	wg     sync.WaitGroup
+}
+
+// NewBus creates a bus and starts its async shards
+func NewBus(opts Options) *Bus {
+	if opts.Shards <= 0 {
+		opts.Shards = 4
+	}
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 256
+	}
+
+	b := &Bus{
+		handlers: make(map[reflect.Type][]handler),
+		done:     make(chan struct{}),
+		shards:   make([]chan delivery, opts.Shards),
+	}
+	for i := range b.shards {
+		b.shards[i] = make(chan delivery, opts.BufferSize)
+		b.wg.Add(1)
+		go b.runShard(b.shards[i])
+	}
+	return b
+}
+
+// SubscribeOption customizes a subscription
+type SubscribeOption func(h *handler)
+
+// Async runs the subscriber outside the publisher's goroutine. Its errors
+// are logged; Publish doesn't wait for it.
+func Async() SubscribeOption {
+	return func(h *handler) { h.async = true }
+}
+
+// Named sets the subscriber name used in logs and errors
+func Named(name string) SubscribeOption {
+	return func(h *handler) { h.name = name }
+}
+
+// Subscribe registers fn for events of type E
+func Subscribe[E Event](b *Bus, fn func(ctx context.Context, event E) error, opts ...SubscribeOption) {
+	t := reflect.TypeOf((*E)(nil)).Elem()
+	h := handler{
+		name: fmt.Sprintf("%v subscriber", t),
+		fn: func(ctx context.Context, event Event) error {
+			return fn(ctx, event.(E))
+		},
+	}
+	for _, opt := range opts {
+		opt(&h)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[t] = append(b.handlers[t], h)
+}
+
+// Publish delivers event to its subscribers. Synchronous subscribers run
+// first, in subscription order; their errors, including panics, are joined
+// and returned. Async subscribers are queued and get a context that keeps
+// ctx's values but not its cancellation.
+func (b *Bus) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	closed := b.closed
+	handlers := b.handlers[reflect.TypeOf(event)]
+	b.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	// Subscribers run without the lock so they can publish or subscribe
+	var errs []error
+	var async []handler
+	for _, h := range handlers {
+		if h.async {
+			async = append(async, h)
+			continue
+		}
+		if err := call(ctx, h, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(async) > 0 {
+		if err := b.enqueue(ctx, delivery{ctx: context.WithoutCancel(ctx), event: event, handlers: async}); err != nil {
+			errs = append(errs, fmt.Errorf("failed to queue %s: %w", event.EventName(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// enqueue hands a delivery to its aggregate's shard. The send happens
+// without the lock, so a full shard blocks only this publisher; Close
+// releases it with ErrClosed and waits for it before closing the shards.
+func (b *Bus) enqueue(ctx context.Context, d delivery) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	b.sending.Add(1)
+	shard := b.shard(d.event.AggregateID())
+	b.mu.RUnlock()
+	defer b.sending.Done()
+
+	select {
+	case shard <- d:
+		return nil
+	case <-b.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events and waits for queued async deliveries.
+// Publishers blocked on a full shard get ErrClosed.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.done)
+	b.mu.Unlock()
+
+	b.sending.Wait()
+	for _, shard := range b.shards {
+		close(shard)
+	}
+	b.wg.Wait()
+}
+
+// shard picks the shard of an aggregate so its events stay in order
+func (b *Bus) shard(aggregateID string) chan delivery {
+	h := fnv.New32a()
+	h.Write([]byte(aggregateID))
+	return b.shards[h.Sum32()%uint32(len(b.shards))]
+}
+
+func (b *Bus) runShard(deliveries chan delivery) {
+	defer b.wg.Done()
+	for d := range deliveries {
+		for _, h := range d.handlers {
+			if err := call(d.ctx, h, d.event); err != nil {
+				log.Printf("events: %v", err)
+			}
+		}
+	}
+}
+
+// call runs one subscriber, turning a panic into an error so it can't
+// affect other subscribers or the publisher
+func call(ctx context.Context, h handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s panicked on %s: %v", h.name, event.EventName(), r)
+		}
+	}()
+	if err := h.fn(ctx, event); err != nil {
+		return fmt.Errorf("%s failed on %s: %w", h.name, event.EventName(), err)
+	}
+	return nil
+}
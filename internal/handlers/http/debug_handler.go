@@ -0,0 +1,75 @@
+package http
+
+import (
+	"bytes"
+	"expvar"
+	"io"
+	"net/http"
+	"net/http/pprof"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// DependencyGraph is a dependency graph that can be rendered as DOT or JSON
+type DependencyGraph interface {
+	WriteDOT(w io.Writer) error
+	WriteJSON(w io.Writer) error
+}
+
+// DebugHandler serves diagnostic information about the running application
+type DebugHandler struct {
+	graph DependencyGraph
+}
+
+// NewDebugHandler creates a new debug handler
+func NewDebugHandler(graph DependencyGraph) *DebugHandler {
+	return &DebugHandler{
+		graph: graph,
+	}
+}
+
+// GetDependencyGraph writes the DI graph; ?format=dot selects DOT, default is JSON
+func (h *DebugHandler) GetDependencyGraph(c *gin.Context) {
+	var buf bytes.Buffer
+
+	switch format := c.DefaultQuery("format", "json"); format {
+	case "dot":
+		if err := h.graph.WriteDOT(&buf); err != nil {
+			response.ErrorInternalServer(c, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", buf.Bytes())
+	case "json":
+		if err := h.graph.WriteJSON(&buf); err != nil {
+			response.ErrorInternalServer(c, err.Error())
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", buf.Bytes())
+	default:
+		response.ErrorBadRequest(c, "format must be one of: dot, json")
+	}
+}
+
+// Pprof serves the net/http/pprof profiles under /debug/pprof/
+func (h *DebugHandler) Pprof(c *gin.Context) {
+	switch path.Base(c.Request.URL.Path) {
+	case "cmdline":
+		pprof.Cmdline(c.Writer, c.Request)
+	case "profile":
+		pprof.Profile(c.Writer, c.Request)
+	case "symbol":
+		pprof.Symbol(c.Writer, c.Request)
+	case "trace":
+		pprof.Trace(c.Writer, c.Request)
+	default:
+		// Index also serves named profiles such as /heap and /goroutine
+		pprof.Index(c.Writer, c.Request)
+	}
+}
+
+// GetMetrics writes the expvar metrics (memstats, cmdline and published vars)
+func (h *DebugHandler) GetMetrics(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
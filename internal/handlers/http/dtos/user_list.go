@@ -0,0 +1,38 @@
+package dtos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// ListUsersRequest is the DTO of the user list query parameters. Sort
+// lists fields, each descending with a leading "-", e.g. -created_at,name.
+// Times are RFC 3339.
+type ListUsersRequest struct {
+	Page        int        `form:"page" json:"page" validate:"min=1"`
+	PageSize    int        `form:"page_size" json:"page_size" validate:"min=1,max=100"`
+	Sort        string     `form:"sort" json:"sort" validate:"sort=id|name|email|created_at|updated_at"`
+	Name        string     `form:"name" json:"name" validate:"max=255"`
+	Email       string     `form:"email" json:"email" validate:"max=255"`
+	CreatedFrom *time.Time `form:"created_from" json:"created_from"`
+	CreatedTo   *time.Time `form:"created_to" json:"created_to"`
+}
+
+// Validate reports every invalid parameter
+func (r *ListUsersRequest) Validate() error {
+	errs := utils.NewValidationErrors()
+	if err := utils.ValidateStruct(r); err != nil && !errors.As(err, &errs) {
+		return err
+	}
+
+	if r.CreatedFrom != nil && r.CreatedTo != nil && !r.CreatedTo.After(*r.CreatedFrom) {
+		errs.Add("created_to", "must be after created_from")
+	}
+
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
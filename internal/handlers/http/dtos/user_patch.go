@@ -0,0 +1,16 @@
+package dtos
+
+// Media types of PATCH request bodies
+const (
+	// MergePatchContentType is a JSON Merge Patch (RFC 7396)
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is a JSON Patch (RFC 6902)
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+// PatchUserResponse is the DTO for patched users, listing the fields the
+// patch changed
+type PatchUserResponse struct {
+	UserResponse
+	Changed []string `json:"changed"`
+}
@@ -0,0 +1,33 @@
+package dtos
+
+import (
+	"strings"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// CreateUserRequest is the DTO for creating a user
+type CreateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Email string `json:"email" validate:"required,max=255,email"`
+}
+
+// Validate trims the fields and reports every invalid one
+func (r *CreateUserRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Email = strings.TrimSpace(r.Email)
+	return utils.ValidateStruct(r)
+}
+
+// UpdateUserRequest is the DTO for replacing a user's fields
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required,max=255"`
+	Email string `json:"email" validate:"required,max=255,email"`
+}
+
+// Validate trims the fields and reports every invalid one
+func (r *UpdateUserRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.Email = strings.TrimSpace(r.Email)
+	return utils.ValidateStruct(r)
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// versionETag is the strong entity tag of a resource version
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// setVersionETag sets the ETag header of a resource version
+func setVersionETag(c *gin.Context, version int64) {
+	c.Header("ETag", versionETag(version))
+}
+
+// ifMatches reports whether an If-Match header value matches etag. Weak
+// tags never match, as If-Match uses the strong comparison.
+func ifMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// lostPrecondition turns the version conflict of a write made under
+// If-Match, at version, into a failed precondition: another writer
+// committed between the If-Match check and the write. The conflict is not
+// wrapped, as response.Error would map it to 409 first.
+func lostPrecondition(err error, version int64) error {
+	var conflict *utils.ConflictError
+	if version != 0 && errors.As(err, &conflict) && conflict.Field == "version" {
+		return utils.NewAppError(utils.CodePreconditionFailed, "the user has changed since it was read")
+	}
+	return err
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/health"
+)
+
+// HealthHandler serves liveness and readiness probes
+type HealthHandler struct {
+	registry *health.Registry
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(registry *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		registry: registry,
+	}
+}
+
+// Livez reports whether the process is alive
+func (h *HealthHandler) Livez(c *gin.Context) {
+	h.respond(c, h.registry.Run(c.Request.Context(), health.Liveness))
+}
+
+// Readyz reports whether the process can serve traffic
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	h.respond(c, h.registry.Run(c.Request.Context(), health.Readiness))
+}
+
+// respond writes the report with 200 when healthy and 503 otherwise
+func (h *HealthHandler) respond(c *gin.Context, report health.Report) {
+	status := http.StatusOK
+	if !report.OK() {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
 	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 )
 
 // ToUserResponse converts a User model to UserResponse
@@ -12,7 +14,8 @@ func ToUserResponse(user *models.User) *dtos.UserResponse {
 	return &dtos.UserResponse{
 		ID:        user.ID,
 		Name:      user.Name,
-		Email:     strings.ReplaceAll(user.Email, "@", ""),
+		Email:     user.Email,
+		Version:   user.Version,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
@@ -25,4 +28,58 @@ func ToUserResponses(users []models.User) []dtos.UserResponse {
 		responses[i] = *ToUserResponse(&user)
 	}
 	return responses
-}
\ No newline at end of file
+}
+
+// FromCreateUserRequest converts a CreateUserRequest to a new User model
+func FromCreateUserRequest(req *dtos.CreateUserRequest) *models.User {
+	return &models.User{
+		Name:  req.Name,
+		Email: req.Email,
+	}
+}
+
+// FromUpdateUserRequest converts an UpdateUserRequest to the User model
+// with the given ID
+func FromUpdateUserRequest(id int64, req *dtos.UpdateUserRequest) *models.User {
+	return &models.User{
+		ID:    id,
+		Name:  req.Name,
+		Email: req.Email,
+	}
+}
+
+// ToUserPatchDocument converts a User model to the document patches of
+// the user apply to
+func ToUserPatchDocument(user *models.User) *dtos.UpdateUserRequest {
+	return &dtos.UpdateUserRequest{
+		Name:  user.Name,
+		Email: user.Email,
+	}
+}
+
+// ToPatchUserResponse converts a patched User model and the fields the
+// patch changed to PatchUserResponse
+func ToPatchUserResponse(user *models.User, changed []string) *dtos.PatchUserResponse {
+	if changed == nil {
+		changed = []string{}
+	}
+	return &dtos.PatchUserResponse{
+		UserResponse: *ToUserResponse(user),
+		Changed:      changed,
+	}
+}
+
+// ToUserQuery converts a validated ListUsersRequest to a UserQuery;
+// missing page parameters get the utils.NewPagination defaults
+func ToUserQuery(req *dtos.ListUsersRequest) repositories.UserQuery {
+	return repositories.UserQuery{
+		Pagination: utils.NewPagination(req.Page, req.PageSize, 0),
+		Sort:       utils.ParseSort(req.Sort),
+		Filter: repositories.UserFilter{
+			NameContains: strings.TrimSpace(req.Name),
+			Email:        strings.TrimSpace(req.Email),
+			CreatedFrom:  req.CreatedFrom,
+			CreatedTo:    req.CreatedTo,
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+)
+
+// DebugRouter handles the runtime debug routes: expvar metrics and pprof.
+// They are only served on the admin listener.
+type DebugRouter struct {
+	handler     *http.DebugHandler
+	middlewares []gin.HandlerFunc
+}
+
+// NewDebugRouter creates a new debug router. middlewares guard every
+// debug route, e.g. the admin token check.
+func NewDebugRouter(handler *http.DebugHandler, middlewares ...gin.HandlerFunc) Router {
+	return &DebugRouter{
+		handler:     handler,
+		middlewares: middlewares,
+	}
+}
+
+// Name returns the route group name
+func (r *DebugRouter) Name() string {
+	return "debug"
+}
+
+// Register registers debug routes
+func (r *DebugRouter) Register(router *gin.Engine) {
+	debugGroup := router.Group("/debug")
+	debugGroup.Use(r.middlewares...)
+	{
+		debugGroup.GET("/vars", r.handler.GetMetrics)
+		debugGroup.GET("/pprof/*profile", r.handler.Pprof)
+		debugGroup.POST("/pprof/symbol", r.handler.Pprof)
+	}
+}
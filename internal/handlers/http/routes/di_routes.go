@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+)
+
+// DIRouter handles the dependency graph admin route
+type DIRouter struct {
+	handler     *http.DebugHandler
+	middlewares []gin.HandlerFunc
+}
+
+// NewDIRouter creates a new DI router. middlewares guard every route,
+// e.g. the admin token check.
+func NewDIRouter(handler *http.DebugHandler, middlewares ...gin.HandlerFunc) Router {
+	return &DIRouter{
+		handler:     handler,
+		middlewares: middlewares,
+	}
+}
+
+// Name returns the route group name
+func (r *DIRouter) Name() string {
+	return "di"
+}
+
+// Register registers DI routes
+func (r *DIRouter) Register(router *gin.Engine) {
+	adminGroup := router.Group("/admin/di")
+	adminGroup.Use(r.middlewares...)
+	{
+		adminGroup.GET("/graph", r.handler.GetDependencyGraph)
+	}
+}
@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+)
+
+// HealthRouter handles liveness and readiness routes
+type HealthRouter struct {
+	handler *http.HealthHandler
+}
+
+// NewHealthRouter creates a new health router
+func NewHealthRouter(handler *http.HealthHandler) Router {
+	return &HealthRouter{
+		handler: handler,
+	}
+}
+
+// Name returns the route group name
+func (r *HealthRouter) Name() string {
+	return "health"
+}
+
+// Register registers health routes
+func (r *HealthRouter) Register(router *gin.Engine) {
+	router.GET("/livez", r.handler.Livez)
+	router.GET("/readyz", r.handler.Readyz)
+	// Kept for existing probes; reports readiness
+	router.GET("/health", r.handler.Readyz)
+}
@@ -1,9 +1,9 @@
 package routes
 
-import (
-	"github.com/gin-gonic/gin"
-	// "github.com/miladev95/golang-project-structure/internal/handlers/http"
-)
+// import (
+// 	"github.com/gin-gonic/gin"
+// 	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+// )
 
 // ProductRouter handles product-related routes
 // type ProductRouter struct {
@@ -39,5 +39,5 @@ import (
 // 2. Create ProductHandler in internal/handlers/http/product_handler.go
 // 3. Follow the same pattern as UserHandler
 // 4. In cmd/server/main.go, get the handler from container and register:
-//    - productHandler, _ := container.GetProductHandler()
+//    - productHandler := di.MustResolve[*http.ProductHandler](container)
 //    - routers = append(routers, routes.NewProductRouter(productHandler))
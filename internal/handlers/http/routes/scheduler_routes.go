@@ -0,0 +1,35 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http"
+)
+
+// SchedulerRouter handles the scheduled task admin routes
+type SchedulerRouter struct {
+	handler     *http.SchedulerHandler
+	middlewares []gin.HandlerFunc
+}
+
+// NewSchedulerRouter creates a new scheduler router. middlewares guard
+// every route, e.g. the admin token check.
+func NewSchedulerRouter(handler *http.SchedulerHandler, middlewares ...gin.HandlerFunc) Router {
+	return &SchedulerRouter{
+		handler:     handler,
+		middlewares: middlewares,
+	}
+}
+
+// Name returns the route group name
+func (r *SchedulerRouter) Name() string {
+	return "scheduler"
+}
+
+// Register registers scheduler routes
+func (r *SchedulerRouter) Register(router *gin.Engine) {
+	adminGroup := router.Group("/admin/scheduler")
+	adminGroup.Use(r.middlewares...)
+	{
+		adminGroup.GET("/tasks", r.handler.GetTasks)
+	}
+}
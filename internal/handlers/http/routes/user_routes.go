@@ -6,15 +6,27 @@ import (
 	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
 )
 
+// UserCachePolicy is the Cache-Control of the user read routes; empty
+// values send none
+type UserCachePolicy struct {
+	// User is the Cache-Control of GET /api/v1/users/:id
+	User string
+	// Users is the Cache-Control of GET /api/v1/users
+	Users string
+}
+
 // UserRouter handles user-related routes
 type UserRouter struct {
 	handler *http.UserHandler
+	cache   UserCachePolicy
 }
 
-// NewUserRouter creates a new user router
-func NewUserRouter(handler *http.UserHandler) Router {
+// NewUserRouter creates a new user router. Reads answer conditional
+// requests with 304 and send the Cache-Control of cache.
+func NewUserRouter(handler *http.UserHandler, cache UserCachePolicy) Router {
 	return &UserRouter{
 		handler: handler,
+		cache:   cache,
 	}
 }
 
@@ -30,8 +42,8 @@ func (r *UserRouter) Register(router *gin.Engine) {
 		// Apply logging middleware to all user routes
 		userGroup.Use(middleware.LoggingMiddleware())
 
-		userGroup.GET("", r.handler.GetAllUsers)
-		userGroup.GET("/:id", r.handler.GetUser)
+		userGroup.GET("", middleware.ConditionalGetMiddleware(r.cache.Users), r.handler.GetAllUsers)
+		userGroup.GET("/:id", middleware.ConditionalGetMiddleware(r.cache.User), r.handler.GetUser)
 
 		// Apply auth middleware only to write operations
 		writeGroup := userGroup.Group("")
@@ -40,6 +52,7 @@ func (r *UserRouter) Register(router *gin.Engine) {
 		{
 			writeGroup.POST("", r.handler.CreateUser)
 			writeGroup.PUT("/:id", r.handler.UpdateUser)
+			writeGroup.PATCH("/:id", r.handler.PatchUser)
 			writeGroup.DELETE("/:id", r.handler.DeleteUser)
 		}
 	}
@@ -0,0 +1,38 @@
+package http
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+	"github.com/miladev95/golang-project-structure/internal/scheduler"
+)
+
+// TaskLister lists scheduled tasks with their last run
+type TaskLister interface {
+	Tasks(ctx context.Context) ([]scheduler.TaskStatus, error)
+}
+
+// SchedulerHandler serves the state of scheduled tasks
+type SchedulerHandler struct {
+	tasks TaskLister
+}
+
+// NewSchedulerHandler creates a new scheduler handler
+func NewSchedulerHandler(tasks TaskLister) *SchedulerHandler {
+	return &SchedulerHandler{
+		tasks: tasks,
+	}
+}
+
+// GetTasks lists every scheduled task with its schedule, next run and the
+// time, duration and error of its last run
+func (h *SchedulerHandler) GetTasks(c *gin.Context) {
+	tasks, err := h.tasks.Tasks(c.Request.Context())
+	if err != nil {
+		response.ErrorInternalServer(c, "Failed to load scheduled tasks")
+		return
+	}
+
+	response.SuccessOK(c, tasks)
+}
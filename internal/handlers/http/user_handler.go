@@ -4,13 +4,15 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
 	"github.com/miladev95/golang-project-structure/internal/handlers/http/mappers"
 	"github.com/miladev95/golang-project-structure/internal/handlers/response"
-	"github.com/miladev95/golang-project-structure/internal/models"
 	"github.com/miladev95/golang-project-structure/internal/services"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 )
 
-// UserHandler handles user-related HTTP requests
+// UserHandler handles user-related HTTP requests. Failures are recorded
+// with c.Error for middleware.ErrorMiddleware to answer.
 type UserHandler struct {
 	userService services.UserService
 }
@@ -22,80 +24,148 @@ func NewUserHandler(userService services.UserService) *UserHandler {
 	}
 }
 
+// GetAllUsers lists a page of users, filtered and sorted by the query
+// parameters of dtos.ListUsersRequest
 func (h *UserHandler) GetAllUsers(c *gin.Context) {
-	users, err := h.userService.GetAllUsers(c.Request.Context())
+	var req dtos.ListUsersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.Error(utils.NewAppErrorWithCause(utils.CodeBadRequest, err.Error(), err))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
+		return
+	}
+
+	query := mappers.ToUserQuery(&req)
+	users, total, err := h.userService.ListUsers(c.Request.Context(), query)
 	if err != nil {
-		response.ErrorInternalServer(c, err.Error())
+		c.Error(err)
 		return
 	}
-	response.SuccessOK(c, mappers.ToUserResponses(users))
+
+	page := utils.NewPagination(query.Pagination.Page, query.Pagination.PageSize, total)
+	response.SuccessPaginated(c, mappers.ToUserResponses(users), response.NewPagination(page))
 }
 
 func (h *UserHandler) GetUser(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseID(c)
 	if err != nil {
-		response.ErrorBadRequest(c, "invalid id")
+		c.Error(err)
 		return
 	}
 
 	user, err := h.userService.GetUser(c.Request.Context(), id)
 	if err != nil {
-		response.ErrorNotFound(c, err.Error())
+		c.Error(err)
 		return
 	}
 
+	setVersionETag(c, user.Version)
+	response.SetLastModified(c, user.UpdatedAt)
 	response.SuccessOK(c, mappers.ToUserResponse(user))
 }
 
 func (h *UserHandler) CreateUser(c *gin.Context) {
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		response.ErrorBadRequest(c, err.Error())
+	var req dtos.CreateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(utils.NewAppErrorWithCause(utils.CodeBadRequest, err.Error(), err))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
 		return
 	}
 
-	createdUser, err := h.userService.CreateUser(c.Request.Context(), &user)
+	createdUser, err := h.userService.CreateUser(c.Request.Context(), mappers.FromCreateUserRequest(&req))
 	if err != nil {
-		response.ErrorInternalServer(c, err.Error())
+		c.Error(err)
 		return
 	}
 
+	setVersionETag(c, createdUser.Version)
 	response.SuccessCreated(c, mappers.ToUserResponse(createdUser))
 }
 
 func (h *UserHandler) UpdateUser(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseID(c)
 	if err != nil {
-		response.ErrorBadRequest(c, "invalid id")
+		c.Error(err)
+		return
+	}
+
+	var req dtos.UpdateUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(utils.NewAppErrorWithCause(utils.CodeBadRequest, err.Error(), err))
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.Error(err)
 		return
 	}
 
-	var user models.User
-	if err := c.ShouldBindJSON(&user); err != nil {
-		response.ErrorBadRequest(c, err.Error())
+	version, err := h.ifMatch(c, id)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
-	user.ID = id
-	if err := h.userService.UpdateUser(c.Request.Context(), &user); err != nil {
-		response.ErrorInternalServer(c, err.Error())
+	user := mappers.FromUpdateUserRequest(id, &req)
+	user.Version = version
+	if err := h.userService.UpdateUser(c.Request.Context(), user); err != nil {
+		c.Error(lostPrecondition(err, version))
 		return
 	}
 
-	response.SuccessOKWithMessage(c, mappers.ToUserResponse(&user), "User updated successfully")
+	setVersionETag(c, user.Version)
+	response.SuccessOKWithMessage(c, mappers.ToUserResponse(user), "User updated successfully")
 }
 
 func (h *UserHandler) DeleteUser(c *gin.Context) {
-	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	id, err := parseID(c)
 	if err != nil {
-		response.ErrorBadRequest(c, "invalid id")
+		c.Error(err)
 		return
 	}
 
-	if err := h.userService.DeleteUser(c.Request.Context(), id); err != nil {
-		response.ErrorInternalServer(c, err.Error())
+	version, err := h.ifMatch(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if err := h.userService.DeleteUser(c.Request.Context(), id, version); err != nil {
+		c.Error(lostPrecondition(err, version))
 		return
 	}
 
 	response.SuccessNoContent(c)
 }
+
+// ifMatch checks the If-Match header against the ETag of the stored user,
+// answering 412 on mismatch. It returns the matched version, for the
+// update to apply only while it is still stored, or 0 without If-Match.
+func (h *UserHandler) ifMatch(c *gin.Context, id int64) (int64, error) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		return 0, nil
+	}
+
+	user, err := h.userService.GetUser(c.Request.Context(), id)
+	if err != nil {
+		return 0, err
+	}
+	if !ifMatches(header, versionETag(user.Version)) {
+		return 0, utils.NewAppError(utils.CodePreconditionFailed, "the user has changed since it was read")
+	}
+	return user.Version, nil
+}
+
+// parseID reads the id path parameter
+func parseID(c *gin.Context) (int64, error) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, utils.NewAppError(utils.CodeBadRequest, "invalid id")
+	}
+	return id, nil
+}
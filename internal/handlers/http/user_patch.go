@@ -0,0 +1,104 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/mappers"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// PatchUser applies a JSON Merge Patch, or a JSON Patch, to the editable
+// fields of a user. Plain application/json bodies are merge patches.
+func (h *UserHandler) PatchUser(c *gin.Context) {
+	id, err := parseID(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		c.Error(utils.NewAppErrorWithCause(utils.CodeBadRequest, "failed to read request body", err))
+		return
+	}
+
+	patch, err := userPatch(c.ContentType(), body)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	version, err := h.ifMatch(c, id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	user, changed, err := h.userService.PatchUser(c.Request.Context(), id, version, patch)
+	if err != nil {
+		c.Error(lostPrecondition(err, version))
+		return
+	}
+
+	setVersionETag(c, user.Version)
+
+	response.SuccessOKWithMessage(c, mappers.ToPatchUserResponse(user, changed), "User updated successfully")
+}
+
+// userPatch returns a function applying body, of the given media type, to
+// a user. The patched document is validated like a PUT body.
+func userPatch(contentType string, body []byte) (func(*models.User) error, error) {
+	var apply func(doc []byte) ([]byte, error)
+	switch contentType {
+	case dtos.MergePatchContentType, "application/json":
+		if !json.Valid(body) {
+			return nil, utils.NewAppError(utils.CodeBadRequest, "invalid merge patch")
+		}
+		apply = func(doc []byte) ([]byte, error) {
+			return jsonpatch.MergePatch(doc, body)
+		}
+	case dtos.JSONPatchContentType:
+		ops, err := jsonpatch.DecodePatch(body)
+		if err != nil {
+			return nil, utils.NewAppErrorWithCause(utils.CodeBadRequest, "invalid JSON patch", err)
+		}
+		apply = ops.Apply
+	default:
+		return nil, utils.NewAppError(utils.CodeUnsupportedMediaType,
+			"Content-Type must be "+dtos.MergePatchContentType+" or "+dtos.JSONPatchContentType)
+	}
+
+	return func(user *models.User) error {
+		doc, err := json.Marshal(mappers.ToUserPatchDocument(user))
+		if err != nil {
+			return err
+		}
+
+		patched, err := apply(doc)
+		if err != nil {
+			// The patch is well formed but does not apply, e.g. a failed
+			// test operation or a missing path
+			return utils.NewAppErrorWithCause(utils.CodeConflict, "patch cannot be applied: "+err.Error(), err)
+		}
+
+		var req dtos.UpdateUserRequest
+		decoder := json.NewDecoder(bytes.NewReader(patched))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&req); err != nil {
+			return utils.NewAppErrorWithCause(utils.CodeBadRequest, err.Error(), err)
+		}
+		if err := req.Validate(); err != nil {
+			return err
+		}
+
+		user.Name, user.Email = req.Name, req.Email
+		return nil
+	}, nil
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// AdminTokenMiddleware requires the static admin token as a bearer token.
+// An empty token rejects every request.
+// Expected header: Authorization: Bearer <token>
+func AdminTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			response.ErrorUnauthorized(c, "Invalid admin token")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/auth"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// ClientCertMiddleware exposes the verified mTLS client certificate as the
+// request's principal; handlers read it with auth.FromContext
+func ClientCertMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		state := c.Request.TLS
+		if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+			response.ErrorUnauthorized(c, "Verified client certificate required")
+			c.Abort()
+			return
+		}
+
+		principal := auth.FromCertificate(state.VerifiedChains[0][0])
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConditionalGetMiddleware answers GET requests whose If-None-Match or
+// If-Modified-Since still match the response with 304 Not Modified.
+// Successful responses get a strong ETag hashed from the body unless the
+// handler set one, and cacheControl as Cache-Control when not empty. The
+// handler sets Last-Modified, e.g. with response.SetLastModified.
+func ConditionalGetMiddleware(cacheControl string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		writer := c.Writer
+		buffer := &bufferedWriter{ResponseWriter: writer}
+		c.Writer = buffer
+		c.Next()
+		c.Writer = writer
+
+		body := buffer.body.Bytes()
+		// Errors are written by ErrorMiddleware; anything written around
+		// the buffer is already on the wire
+		if writer.Status() != http.StatusOK || len(c.Errors) > 0 || writer.Written() {
+			if len(body) > 0 {
+				writer.Write(body)
+			}
+			return
+		}
+
+		header := writer.Header()
+		if header.Get("ETag") == "" {
+			header.Set("ETag", bodyETag(body))
+		}
+		if cacheControl != "" {
+			header.Set("Cache-Control", cacheControl)
+		}
+
+		if notModified(c.Request, header) {
+			header.Del("Content-Type")
+			header.Del("Content-Length")
+			writer.WriteHeader(http.StatusNotModified)
+			writer.WriteHeaderNow()
+			return
+		}
+		writer.Write(body)
+	}
+}
+
+// bufferedWriter holds the body back until the middleware knows whether
+// to send it
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// bodyETag is a strong entity tag of body
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since without it,
+// against the response validators in header
+func notModified(r *http.Request, header http.Header) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		return etagListMatches(inm, header.Get("ETag"))
+	}
+
+	ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+	lastModified, err := http.ParseTime(header.Get("Last-Modified"))
+	if err != nil {
+		return false
+	}
+	return !lastModified.Truncate(time.Second).After(ims)
+}
+
+// etagListMatches reports whether an If-None-Match list matches etag
+// with the weak comparison, which ignores W/ prefixes
+func etagListMatches(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, tag := range strings.Split(list, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,21 +1,33 @@
 package middleware
 
 import (
+	"mime"
+
 	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
 	"github.com/miladev95/golang-project-structure/internal/handlers/response"
 )
 
 // ContentTypeMiddleware ensures requests have proper Content-Type header
-// Applies only to POST, PUT, PATCH requests
+// Applies only to POST, PUT, PATCH requests. PATCH also accepts the JSON
+// Merge Patch and JSON Patch media types; parameters such as charset are
+// ignored.
 func ContentTypeMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		method := c.Request.Method
 
 		// Only validate for methods that typically have a body
 		if method == "POST" || method == "PUT" || method == "PATCH" {
-			contentType := c.GetHeader("Content-Type")
+			mediaType, _, _ := mime.ParseMediaType(c.GetHeader("Content-Type"))
 
-			if contentType != "application/json" {
+			switch {
+			case mediaType == "application/json":
+			case method == "PATCH" && (mediaType == dtos.MergePatchContentType || mediaType == dtos.JSONPatchContentType):
+			case method == "PATCH":
+				response.ErrorBadRequest(c, "Content-Type must be application/json, "+dtos.MergePatchContentType+" or "+dtos.JSONPatchContentType)
+				c.Abort()
+				return
+			default:
 				response.ErrorBadRequest(c, "Content-Type must be application/json")
 				c.Abort()
 				return
@@ -0,0 +1,15 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// ErrorFormatMiddleware sets the format the response package writes errors
+// in. Register it before middleware that can answer with an error.
+func ErrorFormatMiddleware(format response.ErrorFormat) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		response.SetErrorFormat(c, format)
+		c.Next()
+	}
+}
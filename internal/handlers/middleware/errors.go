@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// ErrorMiddleware answers requests whose handler recorded an error with
+// c.Error and wrote no response, mapping the last error with response.Error
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		response.Error(c, c.Errors.Last().Err)
+	}
+}
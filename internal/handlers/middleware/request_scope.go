@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/di/scope"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+)
+
+// RequestScopeMiddleware creates a DI request scope for every request and
+// disposes of it when the request ends. The scope provides the request's
+// context.Context, *http.Request and a *log.Logger prefixed with the method
+// and path, plus any request-scoped providers registered by modules. The
+// request values are read when first resolved, so they include what later
+// middleware adds, e.g. the client certificate principal.
+//
+// Handlers resolve from it through the request context:
+//
+//	logger, err := scope.Resolve[*log.Logger](c.Request.Context())
+func RequestScopeMiddleware(container *di.Container) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestScope, err := container.NewRequestScope(
+			func() context.Context { return c.Request.Context() },
+			func() *http.Request { return c.Request },
+			func() *log.Logger {
+				return log.New(os.Stderr, "["+c.Request.Method+" "+c.Request.URL.Path+"] ", log.LstdFlags)
+			},
+		)
+		if err != nil {
+			log.Printf("Failed to create request scope: %v", err)
+			response.ErrorInternalServer(c, "failed to create request scope")
+			c.Abort()
+			return
+		}
+		defer requestScope.Dispose()
+
+		c.Request = c.Request.WithContext(scope.NewContext(c.Request.Context(), requestScope))
+		c.Next()
+	}
+}
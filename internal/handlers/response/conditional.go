@@ -0,0 +1,17 @@
+package response
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLastModified sets the Last-Modified header, in whole seconds, for
+// middleware.ConditionalGetMiddleware to answer If-Modified-Since with
+func SetLastModified(c *gin.Context, t time.Time) {
+	if t.IsZero() {
+		return
+	}
+	c.Header("Last-Modified", t.UTC().Format(http.TimeFormat))
+}
@@ -0,0 +1,78 @@
+package response
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// Error writes the response for err:
+//
+//	ValidationErrors, ValidationError  422
+//	NotFoundError                      404
+//	ConflictError                      409
+//	UnauthorizedError                  401
+//	ForbiddenError                     403
+//	AppError                           the status of its code in the catalog
+//
+// Anything else, including AppErrors with a code missing from the catalog,
+// is logged with its cause chain and answered with a generic 500, so
+// internal details never reach the client.
+func Error(c *gin.Context, err error) {
+	var (
+		validationErrs *utils.ValidationErrors
+		validationErr  *utils.ValidationError
+		notFound       *utils.NotFoundError
+		conflict       *utils.ConflictError
+		unauthorized   *utils.UnauthorizedError
+		forbidden      *utils.ForbiddenError
+		appErr         *utils.AppError
+	)
+
+	switch {
+	case errors.As(err, &validationErrs):
+		writeCode(c, validationErrs.Code, "", validationErrs.Errors)
+	case errors.As(err, &validationErr):
+		writeCode(c, utils.CodeValidation, "", []utils.ValidationError{*validationErr})
+	case errors.As(err, &notFound):
+		writeCode(c, utils.CodeNotFound, notFound.Error(), nil)
+	case errors.As(err, &conflict):
+		var details interface{}
+		if conflict.Field != "" {
+			details = fieldDetails{Field: conflict.Field}
+		}
+		writeCode(c, utils.CodeConflict, conflict.Message, details)
+	case errors.As(err, &unauthorized):
+		writeCode(c, utils.CodeUnauthorized, unauthorized.Message, nil)
+	case errors.As(err, &forbidden):
+		writeCode(c, utils.CodeForbidden, forbidden.Message, nil)
+	case errors.As(err, &appErr) && isCataloged(appErr.Code):
+		writeCode(c, appErr.Code, appErr.Message, appErr.Details)
+	default:
+		log.Printf("unhandled error on %s %s: %+v", c.Request.Method, c.Request.URL.Path, err)
+		writeCode(c, utils.CodeInternal, "", nil)
+	}
+}
+
+func isCataloged(code string) bool {
+	_, ok := utils.LookupErrorCode(code)
+	return ok
+}
+
+// writeCode writes an error with the status of code from the catalog; an
+// empty message is replaced by the code's default message
+func writeCode(c *gin.Context, code, message string, details interface{}) {
+	status := http.StatusInternalServerError
+	if entry, ok := utils.LookupErrorCode(code); ok {
+		status = entry.Status
+		if message == "" {
+			message = entry.Message
+		}
+	}
+
+	writeError(c, status, code, message, details)
+}
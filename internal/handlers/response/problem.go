@@ -0,0 +1,103 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details
+const ProblemContentType = "application/problem+json"
+
+// errorFormatKey is the gin context key of the request's ErrorFormat
+const errorFormatKey = "response.errorFormat"
+
+// ErrorFormat selects how errors are written for a request
+type ErrorFormat struct {
+	// Problem writes application/problem+json instead of the Response
+	// envelope. Clients can also ask for it with the Accept header.
+	Problem bool
+	// TypeBaseURL prefixes problem types: with "https://example.com/problems/"
+	// a NOT_FOUND error has type https://example.com/problems/not-found.
+	// When empty the type is about:blank.
+	TypeBaseURL string
+}
+
+// Problem is an RFC 7807 problem details object
+type Problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Code is the machine-readable error code from the catalog
+	Code string `json:"code,omitempty"`
+	// Errors lists the invalid fields of validation and conflict errors
+	Errors  []utils.ValidationError `json:"errors,omitempty"`
+	Details interface{}             `json:"details,omitempty"`
+}
+
+// SetErrorFormat sets the error format of the request
+func SetErrorFormat(c *gin.Context, format ErrorFormat) {
+	c.Set(errorFormatKey, format)
+}
+
+// errorFormat returns the request's format; an Accept header listing
+// application/problem+json turns problem details on
+func errorFormat(c *gin.Context) ErrorFormat {
+	format, _ := c.Get(errorFormatKey)
+	f, _ := format.(ErrorFormat)
+	if strings.Contains(c.GetHeader("Accept"), ProblemContentType) {
+		f.Problem = true
+	}
+	return f
+}
+
+// writeError writes an error in the request's format
+func writeError(c *gin.Context, status int, code, message string, details interface{}) {
+	format := errorFormat(c)
+	if !format.Problem {
+		c.JSON(status, Response{
+			Success: false,
+			Error:   message,
+			Code:    code,
+			Details: details,
+		})
+		return
+	}
+
+	problem := Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   message,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+	}
+	if format.TypeBaseURL != "" && code != "" {
+		problem.Type = format.TypeBaseURL + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+		if entry, ok := utils.LookupErrorCode(code); ok && entry.Message != "" {
+			problem.Title = entry.Message
+		}
+	}
+
+	switch d := details.(type) {
+	case []utils.ValidationError:
+		problem.Errors = d
+	case fieldDetails:
+		problem.Errors = []utils.ValidationError{{Field: d.Field, Message: message}}
+	default:
+		problem.Details = details
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(status, problem)
+}
+
+// fieldDetails are the details of an error on one field, e.g. a conflict
+type fieldDetails struct {
+	Field string `json:"field"`
+}
@@ -4,6 +4,8 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 )
 
 // Response is the standard API response envelope
@@ -12,6 +14,9 @@ type Response struct {
 	Data    interface{} `json:"data,omitempty"`
 	Message string      `json:"message,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	// Code and Details describe errors written by Error
+	Code    string      `json:"code,omitempty"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 // PaginatedResponse is for paginated responses
@@ -30,6 +35,16 @@ type Pagination struct {
 	TotalPages  int64 `json:"total_pages"`
 }
 
+// NewPagination converts utils.Pagination to the response metadata
+func NewPagination(p utils.Pagination) Pagination {
+	return Pagination{
+		Total:      p.Total,
+		Page:       p.Page,
+		PageSize:   p.PageSize,
+		TotalPages: int64(p.TotalPage),
+	}
+}
+
 // SuccessOK returns 200 OK with data
 func SuccessOK(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, Response{
@@ -81,64 +96,40 @@ func SuccessPaginated(c *gin.Context, data interface{}, pagination Pagination) {
 
 // ErrorBadRequest returns 400 Bad Request
 func ErrorBadRequest(c *gin.Context, message string) {
-	c.JSON(http.StatusBadRequest, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusBadRequest, utils.CodeBadRequest, message, nil)
 }
 
 // ErrorUnauthorized returns 401 Unauthorized
 func ErrorUnauthorized(c *gin.Context, message string) {
-	c.JSON(http.StatusUnauthorized, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusUnauthorized, utils.CodeUnauthorized, message, nil)
 }
 
 // ErrorForbidden returns 403 Forbidden
 func ErrorForbidden(c *gin.Context, message string) {
-	c.JSON(http.StatusForbidden, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusForbidden, utils.CodeForbidden, message, nil)
 }
 
 // ErrorNotFound returns 404 Not Found
 func ErrorNotFound(c *gin.Context, message string) {
-	c.JSON(http.StatusNotFound, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusNotFound, utils.CodeNotFound, message, nil)
 }
 
 // ErrorConflict returns 409 Conflict
 func ErrorConflict(c *gin.Context, message string) {
-	c.JSON(http.StatusConflict, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusConflict, utils.CodeConflict, message, nil)
 }
 
 // ErrorInternalServer returns 500 Internal Server Error
 func ErrorInternalServer(c *gin.Context, message string) {
-	c.JSON(http.StatusInternalServerError, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusInternalServerError, utils.CodeInternal, message, nil)
 }
 
 // ErrorUnprocessableEntity returns 422 Unprocessable Entity
 func ErrorUnprocessableEntity(c *gin.Context, message string) {
-	c.JSON(http.StatusUnprocessableEntity, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusUnprocessableEntity, utils.CodeValidation, message, nil)
 }
 
 // ErrorTooManyRequests returns 429 Too Many Requests
 func ErrorTooManyRequests(c *gin.Context, message string) {
-	c.JSON(http.StatusTooManyRequests, Response{
-		Success: false,
-		Error:   message,
-	})
+	writeError(c, http.StatusTooManyRequests, utils.CodeTooManyRequests, message, nil)
 }
\ No newline at end of file
@@ -0,0 +1,35 @@
+package health
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+)
+
+// NewDatabaseCheck pings the database
+func NewDatabaseCheck(db *gorm.DB) Checker {
+	return NewCheck("database", func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// NewMigrationCheck fails until every known migration has been applied
+func NewMigrationCheck(db *gorm.DB) Checker {
+	return NewCheck("migrations", func(ctx context.Context) error {
+		current, err := config.CurrentMigrationVersion(db.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if latest := config.LatestMigrationVersion(); current != latest {
+			return fmt.Errorf("schema at version %d, expected %d", current, latest)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,181 @@
+// Package health runs liveness and readiness checks registered by modules.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Group is the dig value group modules provide Registrations into
+const Group = "health.checks"
+
+// Kind selects which endpoint a check belongs to
+type Kind string
+
+const (
+	// Liveness checks report whether the process should be restarted.
+	// Keep them cheap and free of external dependencies.
+	Liveness Kind = "liveness"
+	// Readiness checks report whether the process can serve traffic
+	Readiness Kind = "readiness"
+)
+
+// Status is the outcome of a check or report
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusFail Status = "fail"
+)
+
+// Checker is implemented by anything that can report its health
+type Checker interface {
+	// Name identifies the check in reports
+	Name() string
+	// Check returns an error when the dependency is unhealthy
+	Check(ctx context.Context) error
+}
+
+// Registration is what a module provides to register a check:
+//
+//	container.Provide(func(db *gorm.DB) health.Registration {
+//		return health.Registration{Kind: health.Readiness, Checker: health.NewDatabaseCheck(db)}
+//	}, dig.Group(health.Group))
+type Registration struct {
+	Kind    Kind
+	Checker Checker
+}
+
+// CheckFunc adapts a function to the Checker interface
+type CheckFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewCheck creates a Checker from a name and function
+func NewCheck(name string, fn func(ctx context.Context) error) *CheckFunc {
+	return &CheckFunc{name: name, fn: fn}
+}
+
+// Name returns the check name
+func (c *CheckFunc) Name() string {
+	return c.name
+}
+
+// Check runs the check function
+func (c *CheckFunc) Check(ctx context.Context) error {
+	return c.fn(ctx)
+}
+
+// Result is the latest outcome of one check
+type Result struct {
+	Name        string     `json:"name"`
+	Status      Status     `json:"status"`
+	LatencyMs   float64    `json:"latency_ms"`
+	Error       string     `json:"error,omitempty"`
+	LastError   string     `json:"last_error,omitempty"`
+	LastErrorAt *time.Time `json:"last_error_at,omitempty"`
+	CheckedAt   time.Time  `json:"checked_at"`
+}
+
+// Report is the aggregated outcome of all checks of one kind
+type Report struct {
+	Status Status   `json:"status"`
+	Checks []Result `json:"checks"`
+}
+
+// OK reports whether every check passed
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+// Registry runs registered checks with a timeout and caches their results
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+	checks   map[Kind][]*entry
+}
+
+// entry holds a check and its cached result
+type entry struct {
+	checker Checker
+	mu      sync.Mutex
+	result  Result
+}
+
+// NewRegistry creates a registry. Each check gets timeout to complete, and
+// its result is reused for cacheTTL; a zero cacheTTL disables caching.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	return &Registry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		checks:   make(map[Kind][]*entry),
+	}
+}
+
+// Register adds a check of the given kind. Call it before serving traffic.
+func (r *Registry) Register(kind Kind, checker Checker) *Registry {
+	r.checks[kind] = append(r.checks[kind], &entry{checker: checker})
+	return r
+}
+
+// Run executes all checks of kind in parallel and aggregates their results
+func (r *Registry) Run(ctx context.Context, kind Kind) Report {
+	entries := r.checks[kind]
+	report := Report{Status: StatusOK, Checks: make([]Result, len(entries))}
+
+	var wg sync.WaitGroup
+	for i, e := range entries {
+		wg.Add(1)
+		go func(i int, e *entry) {
+			defer wg.Done()
+			report.Checks[i] = r.run(ctx, e)
+		}(i, e)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+		}
+	}
+	return report
+}
+
+// run executes one check unless its cached result is still fresh
+func (r *Registry) run(ctx context.Context, e *entry) Result {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if r.cacheTTL > 0 && !e.result.CheckedAt.IsZero() && time.Since(e.result.CheckedAt) < r.cacheTTL {
+		return e.result
+	}
+
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := e.checker.Check(ctx)
+
+	result := Result{
+		Name:        e.checker.Name(),
+		Status:      StatusOK,
+		LatencyMs:   float64(time.Since(start).Microseconds()) / 1000,
+		LastError:   e.result.LastError,
+		LastErrorAt: e.result.LastErrorAt,
+		CheckedAt:   time.Now(),
+	}
+	if err != nil {
+		result.Status = StatusFail
+		result.Error = err.Error()
+		result.LastError = err.Error()
+		result.LastErrorAt = &result.CheckedAt
+	}
+
+	e.result = result
+	return result
+}
@@ -0,0 +1,75 @@
+// Package jobs is a persistent background job queue stored in the jobs table.
+// Delivery is at least once: handlers must tolerate running a job twice.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Group is the dig value group modules provide Registrations into
+const Group = "jobs.handlers"
+
+// Status is the lifecycle state of a job
+type Status string
+
+const (
+	// StatusPending jobs wait for RunAt, including failed jobs awaiting a retry
+	StatusPending Status = "pending"
+	// StatusRunning jobs are claimed by a worker
+	StatusRunning Status = "running"
+	// StatusDone jobs completed successfully
+	StatusDone Status = "done"
+	// StatusDead jobs failed MaxAttempts times and are no longer retried
+	StatusDead Status = "dead"
+)
+
+// Job is a unit of background work
+type Job struct {
+	ID          int64      `json:"id" gorm:"primaryKey"`
+	Name        string     `json:"name" gorm:"size:255;not null"`
+	Payload     string     `json:"payload" gorm:"type:text"`
+	Status      Status     `json:"status" gorm:"size:16;not null"`
+	Attempts    int        `json:"attempts" gorm:"not null"`
+	MaxAttempts int        `json:"max_attempts" gorm:"not null"`
+	RunAt       time.Time  `json:"run_at" gorm:"not null"`
+	LastError   string     `json:"last_error,omitempty" gorm:"type:text"`
+	LockedBy    string     `json:"locked_by,omitempty" gorm:"size:255"`
+	LockedAt    *time.Time `json:"locked_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName returns the jobs table name
+func (Job) TableName() string {
+	return "jobs"
+}
+
+// Decode unmarshals the job payload into v
+func (j *Job) Decode(v interface{}) error {
+	return json.Unmarshal([]byte(j.Payload), v)
+}
+
+// Handler runs jobs of one name
+type Handler interface {
+	Handle(ctx context.Context, job *Job) error
+}
+
+// HandlerFunc adapts a function to the Handler interface
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Handle calls f
+func (f HandlerFunc) Handle(ctx context.Context, job *Job) error {
+	return f(ctx, job)
+}
+
+// Registration is what a module provides to handle jobs of one name:
+//
+//	container.Provide(func(mailer Mailer) jobs.Registration {
+//		return jobs.Registration{Name: "send_welcome_email", Handler: jobs.HandlerFunc(...)}
+//	}, dig.Group(jobs.Group))
+type Registration struct {
+	Name    string
+	Handler Handler
+}
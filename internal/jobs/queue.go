@@ -0,0 +1,74 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxAttempts is used when neither the enqueue call nor the queue
+// sets a limit
+const DefaultMaxAttempts = 5
+
+// Queue enqueues jobs for workers to run
+type Queue interface {
+	// Enqueue stores a job; payload is encoded as JSON. Inside
+	// transaction.Manager.WithinTransaction the job commits with the
+	// transaction.
+	Enqueue(ctx context.Context, name string, payload interface{}, opts ...Option) (*Job, error)
+}
+
+// Option customizes an enqueued job
+type Option func(job *Job)
+
+// RunAt delays the job until t
+func RunAt(t time.Time) Option {
+	return func(job *Job) { job.RunAt = t }
+}
+
+// Delay delays the job by d
+func Delay(d time.Duration) Option {
+	return func(job *Job) { job.RunAt = time.Now().Add(d) }
+}
+
+// MaxAttempts sets how many times the job runs before it is dead-lettered
+func MaxAttempts(n int) Option {
+	return func(job *Job) { job.MaxAttempts = n }
+}
+
+type queue struct {
+	store       Store
+	maxAttempts int
+}
+
+// NewQueue creates a queue; maxAttempts is the default retry limit
+func NewQueue(store Store, maxAttempts int) Queue {
+	if maxAttempts <= 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	return &queue{store: store, maxAttempts: maxAttempts}
+}
+
+func (q *queue) Enqueue(ctx context.Context, name string, payload interface{}, opts ...Option) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload of job %q: %w", name, err)
+	}
+
+	job := &Job{
+		Name:        name,
+		Payload:     string(data),
+		Status:      StatusPending,
+		MaxAttempts: q.maxAttempts,
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+
+	if err := q.store.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to enqueue job %q: %w", name, err)
+	}
+	return job, nil
+}
@@ -0,0 +1,115 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+)
+
+// Store persists jobs
+type Store interface {
+	// Create inserts a new job
+	Create(ctx context.Context, job *Job) error
+	// Claim locks the next due job for workerID and marks it running. Jobs
+	// left running for longer than lockTimeout, e.g. by a crashed worker, are
+	// claimed again, or dead-lettered once they have used every attempt. It
+	// returns nil when no job is due.
+	Claim(ctx context.Context, workerID string, lockTimeout time.Duration) (*Job, error)
+	// Complete marks a claimed job done
+	Complete(ctx context.Context, job *Job) error
+	// Fail records a failed attempt; the job is retried at job.RunAt, or
+	// dead-lettered when job.Status is StatusDead
+	Fail(ctx context.Context, job *Job) error
+}
+
+// gormStore is a Store on the jobs table
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewStore creates a Store backed by the database
+func NewStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Create(ctx context.Context, job *Job) error {
+	return transaction.DB(ctx, s.db).Create(job).Error
+}
+
+// Claim selects the job with FOR UPDATE SKIP LOCKED, so concurrent workers
+// never block on or claim the same row
+func (s *gormStore) Claim(ctx context.Context, workerID string, lockTimeout time.Duration) (*Job, error) {
+	var claimed *Job
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		staleBefore := now.Add(-lockTimeout)
+
+		// A worker that died during the last attempt used it up
+		err := tx.Model(&Job{}).
+			Where("status = ? AND locked_at < ? AND attempts >= max_attempts", StatusRunning, staleBefore).
+			Updates(map[string]interface{}{
+				"status":     StatusDead,
+				"last_error": "lock expired on the last attempt",
+				"locked_by":  "",
+				"locked_at":  nil,
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		var job Job
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ? AND attempts < max_attempts)",
+				StatusPending, now, StatusRunning, staleBefore).
+			Order("run_at").
+			Take(&job).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		job.Status = StatusRunning
+		job.Attempts++
+		job.LockedBy = workerID
+		job.LockedAt = &now
+		if err := tx.Model(&job).Select("status", "attempts", "locked_by", "locked_at").Updates(&job).Error; err != nil {
+			return err
+		}
+
+		claimed = &job
+		return nil
+	})
+
+	return claimed, err
+}
+
+func (s *gormStore) Complete(ctx context.Context, job *Job) error {
+	job.Status = StatusDone
+	job.LastError = ""
+	return s.finish(ctx, job)
+}
+
+func (s *gormStore) Fail(ctx context.Context, job *Job) error {
+	return s.finish(ctx, job)
+}
+
+// finish releases the lock and saves the outcome, unless another worker
+// reclaimed the job in the meantime
+func (s *gormStore) finish(ctx context.Context, job *Job) error {
+	owner := job.LockedBy
+	job.LockedBy = ""
+	job.LockedAt = nil
+
+	return s.db.WithContext(ctx).Model(job).
+		Where("locked_by = ?", owner).
+		Select("status", "run_at", "last_error", "locked_by", "locked_at").
+		Updates(job).Error
+}
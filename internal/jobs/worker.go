@@ -0,0 +1,154 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// ErrNoHandler is the failure recorded for jobs no handler is registered for
+var ErrNoHandler = errors.New("no handler registered")
+
+// Options configures a Worker
+type Options struct {
+	// Concurrency is the number of jobs run in parallel
+	Concurrency int
+	// PollInterval is how long an idle worker waits before polling again
+	PollInterval time.Duration
+	// LockTimeout is how long a job may run before another worker reclaims it
+	LockTimeout time.Duration
+	// Backoff returns the delay before retrying after the given attempt;
+	// defaults to utils.ExponentialBackoff(time.Second, time.Hour)
+	Backoff func(attempt int) time.Duration
+}
+
+// Worker claims due jobs and runs them with their registered handlers
+type Worker struct {
+	store    Store
+	handlers map[string]Handler
+	opts     Options
+	id       string
+}
+
+// NewWorker creates a worker for the given handler registrations
+func NewWorker(store Store, registrations []Registration, opts Options) (*Worker, error) {
+	handlers := make(map[string]Handler, len(registrations))
+	for _, r := range registrations {
+		if _, ok := handlers[r.Name]; ok {
+			return nil, fmt.Errorf("duplicate handler for job %q", r.Name)
+		}
+		handlers[r.Name] = r.Handler
+	}
+
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 1
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.LockTimeout <= 0 {
+		opts.LockTimeout = 5 * time.Minute
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = utils.ExponentialBackoff(time.Second, time.Hour)
+	}
+
+	hostname, _ := os.Hostname()
+	return &Worker{
+		store:    store,
+		handlers: handlers,
+		opts:     opts,
+		id:       fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}, nil
+}
+
+// Run processes jobs with Options.Concurrency goroutines until ctx is
+// cancelled, then waits for running jobs to finish
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < w.opts.Concurrency; i++ {
+		wg.Add(1)
+		go func(slot int) {
+			defer wg.Done()
+			w.loop(ctx, fmt.Sprintf("%s/%d", w.id, slot))
+		}(i)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (w *Worker) loop(ctx context.Context, workerID string) {
+	for ctx.Err() == nil {
+		ran, err := w.runOnce(ctx, workerID)
+		if err != nil {
+			log.Printf("jobs: %v", err)
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.opts.PollInterval):
+		}
+	}
+}
+
+// RunOnce claims and runs at most one due job. It reports whether a job ran.
+func (w *Worker) RunOnce(ctx context.Context) (bool, error) {
+	return w.runOnce(ctx, w.id)
+}
+
+func (w *Worker) runOnce(ctx context.Context, workerID string) (bool, error) {
+	job, err := w.store.Claim(ctx, workerID, w.opts.LockTimeout)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	// Finish the job even when shutdown cancels ctx mid-run
+	finishCtx := context.WithoutCancel(ctx)
+
+	if err := w.handle(ctx, job); err != nil {
+		job.LastError = err.Error()
+		if job.Attempts >= job.MaxAttempts {
+			job.Status = StatusDead
+			log.Printf("jobs: job %d (%s) is dead after %d attempts: %v", job.ID, job.Name, job.Attempts, err)
+		} else {
+			job.Status = StatusPending
+			job.RunAt = time.Now().Add(w.opts.Backoff(job.Attempts))
+		}
+		if err := w.store.Fail(finishCtx, job); err != nil {
+			return true, fmt.Errorf("failed to record failure of job %d: %w", job.ID, err)
+		}
+		return true, nil
+	}
+
+	if err := w.store.Complete(finishCtx, job); err != nil {
+		return true, fmt.Errorf("failed to complete job %d: %w", job.ID, err)
+	}
+	return true, nil
+}
+
+// handle runs the job's handler, turning a panic into an error
+func (w *Worker) handle(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Name]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandler, job.Name)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler.Handle(ctx, job)
+}
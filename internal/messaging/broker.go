@@ -0,0 +1,63 @@
+package messaging
+
+import (
+	"context"
+	"strings"
+)
+
+// Group is the dig value group modules provide consumer Registrations into
+const Group = "messaging.consumers"
+
+// Handler processes one delivered event. Returning an error asks the broker
+// to redeliver it.
+type Handler func(ctx context.Context, env Envelope) error
+
+// Publisher publishes events on the subject named by their type
+type Publisher interface {
+	Publish(ctx context.Context, env Envelope) error
+}
+
+// Subscriber delivers events to handlers. Subscriptions sharing a queue
+// group split the events between them; each group gets every event.
+type Subscriber interface {
+	Subscribe(subject, group string, handler Handler) (Subscription, error)
+}
+
+// Subscription is an active subscription
+type Subscription interface {
+	Unsubscribe() error
+}
+
+// Broker publishes and subscribes
+type Broker interface {
+	Publisher
+	Subscriber
+	Close() error
+}
+
+// Registration is what a module provides to consume events from the broker.
+// Handlers are wrapped with Idempotent using Group as the consumer name.
+type Registration struct {
+	// Subject may use NATS wildcards: "*" matches one token, ">" the rest
+	Subject string
+	// Group names the consumer; it must be unique per handler
+	Group   string
+	Handler Handler
+}
+
+// subjectMatches reports whether subject matches pattern using NATS
+// wildcard rules
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) || (token != "*" && token != subjectTokens[i]) {
+			return false
+		}
+	}
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -0,0 +1,63 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+)
+
+// Consumers subscribes the registered handlers for as long as it runs
+type Consumers struct {
+	subscriber    Subscriber
+	tx            transaction.Manager
+	store         ProcessedStore
+	registrations []Registration
+}
+
+// NewConsumers validates the registrations; groups must be unique because
+// they name the consumer in the broker and in the processed events table
+func NewConsumers(subscriber Subscriber, tx transaction.Manager, store ProcessedStore, registrations []Registration) (*Consumers, error) {
+	seen := make(map[string]bool)
+	for _, r := range registrations {
+		if r.Subject == "" || r.Group == "" || r.Handler == nil {
+			return nil, fmt.Errorf("consumer %q needs a subject, group and handler", r.Group)
+		}
+		if seen[r.Group] {
+			return nil, fmt.Errorf("consumer group %q registered twice", r.Group)
+		}
+		seen[r.Group] = true
+	}
+
+	return &Consumers{subscriber: subscriber, tx: tx, store: store, registrations: registrations}, nil
+}
+
+// Len returns the number of registered consumers
+func (c *Consumers) Len() int {
+	return len(c.registrations)
+}
+
+// Run subscribes every consumer and unsubscribes them when ctx is done
+func (c *Consumers) Run(ctx context.Context) error {
+	subs := make([]Subscription, 0, len(c.registrations))
+	unsubscribe := func() error {
+		var errs []error
+		for _, sub := range subs {
+			errs = append(errs, sub.Unsubscribe())
+		}
+		return errors.Join(errs...)
+	}
+
+	for _, r := range c.registrations {
+		sub, err := c.subscriber.Subscribe(r.Subject, r.Group, Idempotent(c.tx, c.store, r.Group, r.Handler))
+		if err != nil {
+			unsubscribe()
+			return fmt.Errorf("failed to subscribe %s to %s: %w", r.Group, r.Subject, err)
+		}
+		subs = append(subs, sub)
+	}
+
+	<-ctx.Done()
+	return unsubscribe()
+}
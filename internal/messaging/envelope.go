@@ -0,0 +1,60 @@
+// Package messaging publishes domain events to a message broker and consumes
+// them. Every event travels in a versioned Envelope; delivery is at least
+// once, so consumers wrap their handlers with Idempotent.
+package messaging
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Envelope is the wire format of every event on the broker
+type Envelope struct {
+	// ID is unique per event and stays the same across redeliveries
+	ID string `json:"id"`
+	// Type is the event type and the subject it is published on, e.g.
+	// "user.created"
+	Type string `json:"type"`
+	// OccurredAt is when the change happened, not when it was published
+	OccurredAt time.Time `json:"occurred_at"`
+	// SchemaVersion is the version of the payload's shape for Type
+	SchemaVersion int `json:"schema_version"`
+	// AggregateID is the ID of the entity the event is about
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope wraps payload, encoded as JSON, in an envelope with a new ID
+func NewEnvelope(eventType string, schemaVersion int, aggregateID string, payload interface{}) (Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to encode %s payload: %w", eventType, err)
+	}
+
+	return Envelope{
+		ID:            newID(),
+		Type:          eventType,
+		OccurredAt:    time.Now().UTC(),
+		SchemaVersion: schemaVersion,
+		AggregateID:   aggregateID,
+		Payload:       data,
+	}, nil
+}
+
+// Decode unmarshals the payload into v
+func (e Envelope) Decode(v interface{}) error {
+	return json.Unmarshal(e.Payload, v)
+}
+
+// newID returns a random version 4 UUID
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("messaging: failed to generate event ID: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
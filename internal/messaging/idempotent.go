@@ -0,0 +1,71 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+)
+
+// ProcessedEvent records that a consumer handled an event
+type ProcessedEvent struct {
+	Consumer    string    `gorm:"primaryKey;size:255"`
+	EventID     string    `gorm:"primaryKey;size:64"`
+	ProcessedAt time.Time `gorm:"not null"`
+}
+
+// TableName returns the processed events table name
+func (ProcessedEvent) TableName() string {
+	return "processed_events"
+}
+
+// ProcessedStore remembers which events each consumer handled
+type ProcessedStore interface {
+	// Claim records eventID as handled by consumer. It reports false when
+	// the event was already claimed; inside a transaction, a concurrent
+	// claim of the same event waits for that transaction to finish.
+	Claim(ctx context.Context, consumer, eventID string) (bool, error)
+}
+
+// gormProcessedStore is a ProcessedStore on the processed_events table
+type gormProcessedStore struct {
+	db *gorm.DB
+}
+
+// NewProcessedStore creates a ProcessedStore backed by the database
+func NewProcessedStore(db *gorm.DB) ProcessedStore {
+	return &gormProcessedStore{db: db}
+}
+
+func (s *gormProcessedStore) Claim(ctx context.Context, consumer, eventID string) (bool, error) {
+	result := transaction.DB(ctx, s.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&ProcessedEvent{Consumer: consumer, EventID: eventID, ProcessedAt: time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Idempotent runs handler at most once per event and consumer. The event
+// is claimed and handled in one transaction: a handler error rolls the
+// claim back so the redelivery runs again, and the handler's own writes
+// through transaction.DB commit together with the claim.
+func Idempotent(tx transaction.Manager, store ProcessedStore, consumer string, handler Handler) Handler {
+	return func(ctx context.Context, env Envelope) error {
+		return tx.WithinTransaction(ctx, func(ctx context.Context) error {
+			claimed, err := store.Claim(ctx, consumer, env.ID)
+			if err != nil {
+				return fmt.Errorf("failed to claim event %s: %w", env.ID, err)
+			}
+			if !claimed {
+				return nil
+			}
+			return handler(ctx, env)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+)
+
+// DefaultMaxDeliver is how often a failing event is delivered before the
+// in-memory broker drops it
+const DefaultMaxDeliver = 3
+
+// MemoryBroker is an in-process Broker for tests and single-instance
+// development. Events are delivered synchronously from Publish; a handler
+// error redelivers the event up to MaxDeliver times.
+type MemoryBroker struct {
+	// MaxDeliver caps the deliveries of one event to one group
+	MaxDeliver int
+
+	mu     sync.Mutex
+	subs   []*memorySubscription
+	next   map[string]int
+	closed bool
+}
+
+// memorySubscription is one handler on a MemoryBroker
+type memorySubscription struct {
+	broker  *MemoryBroker
+	subject string
+	group   string
+	handler Handler
+}
+
+// NewMemoryBroker creates an empty in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{MaxDeliver: DefaultMaxDeliver, next: make(map[string]int)}
+}
+
+// Publish delivers env to one subscription of every matching group
+func (b *MemoryBroker) Publish(ctx context.Context, env Envelope) error {
+	targets, err := b.targets(env.Type)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range targets {
+		sub.deliver(ctx, env)
+	}
+	return nil
+}
+
+// targets picks a subscription per group, round-robin within the group
+func (b *MemoryBroker) targets(subject string) ([]*memorySubscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, fmt.Errorf("memory broker is closed")
+	}
+
+	members := make(map[string][]*memorySubscription)
+	var groups []string
+	for _, sub := range b.subs {
+		if !subjectMatches(sub.subject, subject) {
+			continue
+		}
+		if _, ok := members[sub.group]; !ok {
+			groups = append(groups, sub.group)
+		}
+		members[sub.group] = append(members[sub.group], sub)
+	}
+
+	targets := make([]*memorySubscription, 0, len(groups))
+	for _, group := range groups {
+		subs := members[group]
+		targets = append(targets, subs[b.next[group]%len(subs)])
+		b.next[group]++
+	}
+	return targets, nil
+}
+
+func (s *memorySubscription) deliver(ctx context.Context, env Envelope) {
+	maxDeliver := s.broker.MaxDeliver
+	if maxDeliver <= 0 {
+		maxDeliver = DefaultMaxDeliver
+	}
+
+	for attempt := 1; attempt <= maxDeliver; attempt++ {
+		err := s.handler(ctx, env)
+		if err == nil {
+			return
+		}
+		log.Printf("messaging: %s on %s failed (delivery %d/%d): %v", s.group, env.Type, attempt, maxDeliver, err)
+	}
+}
+
+// Subscribe registers handler for subject in group
+func (b *MemoryBroker) Subscribe(subject, group string, handler Handler) (Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, fmt.Errorf("memory broker is closed")
+	}
+
+	sub := &memorySubscription{broker: b, subject: subject, group: group, handler: handler}
+	b.subs = append(b.subs, sub)
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription
+func (s *memorySubscription) Unsubscribe() error {
+	b := s.broker
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, sub := range b.subs {
+		if sub == s {
+			b.subs = append(b.subs[:i], b.subs[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Close stops the broker; later publishes and subscribes fail
+func (b *MemoryBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	b.subs = nil
+	return nil
+}
@@ -0,0 +1,157 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// NATSOptions configures a NATSBroker
+type NATSOptions struct {
+	URL string
+	// Stream is the JetStream stream holding the events; it is created with
+	// Subjects when missing
+	Stream   string
+	Subjects []string
+	// AckWait is how long a handler has before the event is redelivered
+	AckWait time.Duration
+	// MaxDeliver caps deliveries of one event to one group; 0 is unlimited
+	MaxDeliver int
+}
+
+// NATSBroker is a Broker on NATS JetStream. Events are stored in a stream
+// and every group is a durable consumer that acknowledges an event only
+// after its handler succeeds.
+type NATSBroker struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	opts    NATSOptions
+	backoff func(attempt int) time.Duration
+}
+
+// NewNATSBroker connects to NATS and makes sure the stream exists
+func NewNATSBroker(opts NATSOptions) (*NATSBroker, error) {
+	if opts.AckWait <= 0 {
+		opts.AckWait = 30 * time.Second
+	}
+	if opts.MaxDeliver <= 0 {
+		opts.MaxDeliver = -1
+	}
+
+	conn, err := nats.Connect(opts.URL, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open JetStream: %w", err)
+	}
+
+	if _, err := js.StreamInfo(opts.Stream); errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{Name: opts.Stream, Subjects: opts.Subjects})
+		if err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to create stream %s: %w", opts.Stream, err)
+		}
+	} else if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to look up stream %s: %w", opts.Stream, err)
+	}
+
+	return &NATSBroker{
+		conn:    conn,
+		js:      js,
+		opts:    opts,
+		backoff: utils.ExponentialBackoff(time.Second, time.Minute),
+	}, nil
+}
+
+// Publish stores env in the stream. The envelope ID is the JetStream
+// message ID, but the server only drops repeats within its duplicate
+// window (two minutes by default); consumers rely on Idempotent instead.
+func (b *NATSBroker) Publish(ctx context.Context, env Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	msg := nats.NewMsg(env.Type)
+	msg.Data = data
+	msg.Header.Set(nats.MsgIdHdr, env.ID)
+	_, err = b.js.PublishMsg(msg, nats.Context(ctx))
+	return err
+}
+
+// Subscribe binds handler to the durable consumer named after group,
+// creating it when missing. The consumer outlives the subscription, so
+// events published while no instance runs are delivered on restart.
+func (b *NATSBroker) Subscribe(subject, group string, handler Handler) (Subscription, error) {
+	name := consumerName(group)
+
+	if _, err := b.js.ConsumerInfo(b.opts.Stream, name); errors.Is(err, nats.ErrConsumerNotFound) {
+		_, err = b.js.AddConsumer(b.opts.Stream, &nats.ConsumerConfig{
+			Durable:        name,
+			DeliverSubject: "_deliver." + name,
+			DeliverGroup:   name,
+			FilterSubject:  subject,
+			DeliverPolicy:  nats.DeliverAllPolicy,
+			AckPolicy:      nats.AckExplicitPolicy,
+			AckWait:        b.opts.AckWait,
+			MaxDeliver:     b.opts.MaxDeliver,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create consumer %s: %w", name, err)
+		}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to look up consumer %s: %w", name, err)
+	}
+
+	return b.js.QueueSubscribe(subject, name, func(msg *nats.Msg) {
+		b.handle(msg, group, handler)
+	}, nats.Bind(b.opts.Stream, name), nats.ManualAck())
+}
+
+// handle acknowledges msg when handler succeeds and asks for a delayed
+// redelivery when it fails. Envelopes that can't be decoded are dropped.
+func (b *NATSBroker) handle(msg *nats.Msg, group string, handler Handler) {
+	var env Envelope
+	if err := json.Unmarshal(msg.Data, &env); err != nil {
+		log.Printf("messaging: %s dropped an undecodable message on %s: %v", group, msg.Subject, err)
+		msg.Term()
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.AckWait)
+	defer cancel()
+
+	if err := handler(ctx, env); err != nil {
+		attempt := 1
+		if meta, metaErr := msg.Metadata(); metaErr == nil {
+			attempt = int(meta.NumDelivered)
+		}
+		log.Printf("messaging: %s on %s %s failed (delivery %d): %v", group, env.Type, env.ID, attempt, err)
+		msg.NakWithDelay(b.backoff(attempt))
+		return
+	}
+	msg.Ack()
+}
+
+// Close drains subscriptions and closes the connection
+func (b *NATSBroker) Close() error {
+	return b.conn.Drain()
+}
+
+// consumerName turns a group into a valid JetStream consumer name
+func consumerName(group string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(group)
+}
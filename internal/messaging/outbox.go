@@ -0,0 +1,34 @@
+package messaging
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/miladev95/golang-project-structure/internal/outbox"
+)
+
+// NewOutboxPublisher relays outbox messages to publisher. Messages whose
+// payload is an Envelope are published as is; any other payload is wrapped
+// in a version 1 envelope whose ID is derived from the message ID.
+func NewOutboxPublisher(publisher Publisher) outbox.Publisher {
+	return outbox.PublisherFunc(func(ctx context.Context, msg *outbox.Message) error {
+		return publisher.Publish(ctx, envelopeFromMessage(msg))
+	})
+}
+
+func envelopeFromMessage(msg *outbox.Message) Envelope {
+	var env Envelope
+	if err := json.Unmarshal([]byte(msg.Payload), &env); err == nil && env.ID != "" && env.Type != "" {
+		return env
+	}
+
+	return Envelope{
+		ID:            "outbox-" + strconv.FormatInt(msg.ID, 10),
+		Type:          msg.Topic,
+		OccurredAt:    msg.CreatedAt.UTC(),
+		SchemaVersion: 1,
+		AggregateID:   msg.AggregateID,
+		Payload:       json.RawMessage(msg.Payload),
+	}
+}
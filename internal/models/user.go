@@ -2,11 +2,13 @@ package models
 
 import "time"
 
-// User domain model
+// User domain model. Version is incremented by every update, for
+// optimistic concurrency control.
 type User struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
 	Email     string    `json:"email"`
+	Version   int64     `json:"version" gorm:"not null;default:1"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
-}
\ No newline at end of file
+}
@@ -0,0 +1,72 @@
+// Package outbox implements the transactional outbox: events are written to
+// the outbox table in the same transaction as the change they describe, and
+// a relay publishes them once committed. Delivery is at least once.
+package outbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Status is the delivery state of a message
+type Status string
+
+const (
+	// StatusPending messages wait for NextAttemptAt, including failed ones
+	StatusPending Status = "pending"
+	// StatusSent messages were published
+	StatusSent Status = "sent"
+)
+
+// Message is an event waiting in, or published from, the outbox
+type Message struct {
+	ID            int64      `json:"id" gorm:"primaryKey"`
+	Topic         string     `json:"topic" gorm:"size:255;not null"`
+	AggregateID   string     `json:"aggregate_id" gorm:"size:255"`
+	Payload       string     `json:"payload" gorm:"type:text"`
+	Status        Status     `json:"status" gorm:"size:16;not null"`
+	Attempts      int        `json:"attempts" gorm:"not null"`
+	NextAttemptAt time.Time  `json:"next_attempt_at" gorm:"not null"`
+	LastError     string     `json:"last_error,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+}
+
+// TableName returns the outbox table name
+func (Message) TableName() string {
+	return "outbox"
+}
+
+// NewMessage creates a pending message; payload is encoded as JSON
+func NewMessage(topic, aggregateID string, payload interface{}) (*Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s payload: %w", topic, err)
+	}
+
+	now := time.Now()
+	return &Message{
+		Topic:         topic,
+		AggregateID:   aggregateID,
+		Payload:       string(data),
+		Status:        StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Write stores a message using tx, which must be the transaction that makes
+// the change the message describes
+func Write(tx *gorm.DB, topic, aggregateID string, payload interface{}) error {
+	msg, err := NewMessage(topic, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(msg).Error; err != nil {
+		return fmt.Errorf("failed to write %s to outbox: %w", topic, err)
+	}
+	return nil
+}
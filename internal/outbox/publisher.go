@@ -0,0 +1,140 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Publisher delivers outbox messages to other systems
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface
+type PublisherFunc func(ctx context.Context, msg *Message) error
+
+// Publish calls f
+func (f PublisherFunc) Publish(ctx context.Context, msg *Message) error {
+	return f(ctx, msg)
+}
+
+// LogPublisher writes messages to a logger; useful in development
+type LogPublisher struct {
+	logger *log.Logger
+}
+
+// NewLogPublisher creates a publisher that logs to logger, or the standard
+// logger when nil
+func NewLogPublisher(logger *log.Logger) *LogPublisher {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the message
+func (p *LogPublisher) Publish(ctx context.Context, msg *Message) error {
+	p.logger.Printf("outbox: %s #%d (%s) %s", msg.Topic, msg.ID, msg.AggregateID, msg.Payload)
+	return nil
+}
+
+// WebhookPublisher POSTs each message as JSON to a URL. With a secret, the
+// body is signed with HMAC-SHA256 in the X-Outbox-Signature header.
+type WebhookPublisher struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewWebhookPublisher creates a webhook publisher; client defaults to one
+// with a 10 second timeout
+func NewWebhookPublisher(url, secret string, client *http.Client) *WebhookPublisher {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookPublisher{url: url, secret: []byte(secret), client: client}
+}
+
+// webhookBody is the JSON body sent for a message
+type webhookBody struct {
+	ID          int64           `json:"id"`
+	Topic       string          `json:"topic"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+// Publish sends the message; any non-2xx response is an error
+func (p *WebhookPublisher) Publish(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(webhookBody{
+		ID:          msg.ID,
+		Topic:       msg.Topic,
+		AggregateID: msg.AggregateID,
+		Payload:     json.RawMessage(msg.Payload),
+		CreatedAt:   msg.CreatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Outbox-Topic", msg.Topic)
+	// Receivers deduplicate redeliveries by message ID
+	req.Header.Set("X-Outbox-Message-ID", strconv.FormatInt(msg.ID, 10))
+	if len(p.secret) > 0 {
+		mac := hmac.New(sha256.New, p.secret)
+		mac.Write(body)
+		req.Header.Set("X-Outbox-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with %s", resp.Status)
+	}
+	return nil
+}
+
+// MemoryPublisher keeps published messages in memory, for tests
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewMemoryPublisher creates an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// Publish records the message
+func (p *MemoryPublisher) Publish(ctx context.Context, msg *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.messages = append(p.messages, *msg)
+	return nil
+}
+
+// Messages returns the published messages in order
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.messages...)
+}
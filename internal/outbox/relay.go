@@ -0,0 +1,98 @@
+package outbox
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// Options configures a Relay
+type Options struct {
+	// BatchSize is the number of messages claimed and published at a time
+	BatchSize int
+	// Lease is how long claimed messages are hidden from other relays; a
+	// batch should publish well within it. Defaults to 5 minutes.
+	Lease time.Duration
+	// PollInterval is how long an idle relay waits before polling again
+	PollInterval time.Duration
+	// Backoff returns the delay before retrying after the given attempt;
+	// defaults to utils.ExponentialBackoff(time.Second, 10*time.Minute)
+	Backoff func(attempt int) time.Duration
+}
+
+// Relay publishes pending outbox messages. Failed messages are retried with
+// backoff until they are published; none are dropped.
+type Relay struct {
+	store     Store
+	publisher Publisher
+	opts      Options
+}
+
+// NewRelay creates a relay that publishes through publisher
+func NewRelay(store Store, publisher Publisher, opts Options) *Relay {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = 5 * time.Minute
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = time.Second
+	}
+	if opts.Backoff == nil {
+		opts.Backoff = utils.ExponentialBackoff(time.Second, 10*time.Minute)
+	}
+	return &Relay{store: store, publisher: publisher, opts: opts}
+}
+
+// Run relays messages until ctx is cancelled
+func (r *Relay) Run(ctx context.Context) error {
+	for ctx.Err() == nil {
+		n, err := r.RelayOnce(ctx)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+		}
+		// A full batch means more may be waiting
+		if err == nil && n == r.opts.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(r.opts.PollInterval):
+		}
+	}
+	return nil
+}
+
+// RelayOnce publishes one batch of due messages and returns how many were
+// attempted. No transaction is open while publishing.
+func (r *Relay) RelayOnce(ctx context.Context) (int, error) {
+	messages, err := r.store.Claim(ctx, r.opts.BatchSize, r.opts.Lease)
+	if err != nil || len(messages) == 0 {
+		return 0, err
+	}
+
+	for _, msg := range messages {
+		r.publish(ctx, msg)
+	}
+	// Saved even when ctx is done so published messages aren't resent
+	return len(messages), r.store.Save(context.WithoutCancel(ctx), messages)
+}
+
+func (r *Relay) publish(ctx context.Context, msg *Message) {
+	msg.Attempts++
+
+	if err := r.publisher.Publish(ctx, msg); err != nil {
+		msg.LastError = err.Error()
+		msg.NextAttemptAt = time.Now().Add(r.opts.Backoff(msg.Attempts))
+		return
+	}
+
+	now := time.Now()
+	msg.Status = StatusSent
+	msg.SentAt = &now
+	msg.LastError = ""
+}
@@ -0,0 +1,75 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store gives the relay access to pending messages
+type Store interface {
+	// Claim leases up to limit due messages by moving their next attempt
+	// lease into the future, so other relays skip them and a relay that
+	// crashes mid-batch leaves them to be retried once the lease expires
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*Message, error)
+	// Save stores the delivery state of claimed messages
+	Save(ctx context.Context, messages []*Message) error
+}
+
+// gormStore is a Store on the outbox table
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewStore creates a Store backed by the database
+func NewStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+// Claim takes the row locks only for as long as it takes to lease the
+// rows; publishing happens after the transaction commits
+func (s *gormStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*Message, error) {
+	var messages []*Message
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", StatusPending, now).
+			Order("id").
+			Limit(limit).
+			Find(&messages).Error
+		if err != nil || len(messages) == 0 {
+			return err
+		}
+
+		ids := make([]int64, len(messages))
+		for i, msg := range messages {
+			ids[i] = msg.ID
+			msg.NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&Message{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (s *gormStore) Save(ctx context.Context, messages []*Message) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, msg := range messages {
+			err := tx.Model(msg).
+				Select("status", "attempts", "next_attempt_at", "last_error", "sent_at").
+				Updates(msg).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// Postgres SQLSTATE codes
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgLockNotAvailable     = "55P03"
+	pgQueryCanceled        = "57014"
+)
+
+// MySQL error numbers
+const (
+	mysqlDuplicateEntry      = 1062
+	mysqlRowIsReferenced     = 1451
+	mysqlNoReferencedRow     = 1452
+	mysqlCheckViolation      = 3819
+	mysqlLockWaitTimeout     = 1205
+	mysqlDeadlock            = 1213
+	mysqlMaxExecutionTimeout = 3024
+)
+
+var (
+	// pgKeyDetail matches the detail of a Postgres unique violation:
+	// Key (email)=(jane@example.com) already exists.
+	pgKeyDetail = regexp.MustCompile(`^Key \(([^)]+)\)=`)
+	// mysqlDuplicateKey matches the key of a MySQL duplicate entry message:
+	// Duplicate entry 'jane@example.com' for key 'users.email'
+	mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+)
+
+// TranslateError turns gorm and driver errors into the errors in
+// pkg/utils, so callers don't depend on the database in use. resource and
+// id describe the record for not found errors. Unknown errors are returned
+// unchanged.
+func TranslateError(err error, resource string, id interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return utils.NewNotFoundError(resource, id)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return utils.NewAppErrorWithCause(utils.CodeTimeout, "the database did not respond in time", err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return translatePostgres(pgErr, resource)
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return translateMySQL(mysqlErr, resource)
+	}
+
+	return err
+}
+
+func translatePostgres(err *pgconn.PgError, resource string) error {
+	switch err.Code {
+	case pgUniqueViolation:
+		field := ""
+		if m := pgKeyDetail.FindStringSubmatch(err.Detail); m != nil {
+			field = m[1]
+		} else {
+			field = fieldFromConstraint(err.ConstraintName, err.TableName)
+		}
+		return conflict(resource, field)
+	case pgForeignKeyViolation:
+		return utils.NewAppErrorWithCause(utils.CodeForeignKeyViolation, "a referenced record does not exist or is still referenced", err)
+	case pgCheckViolation:
+		return utils.NewAppErrorWithCause(utils.CodeCheckViolation, fmt.Sprintf("the %s violates constraint %s", resource, err.ConstraintName), err)
+	case pgDeadlockDetected, pgSerializationFailure:
+		return utils.NewAppErrorWithCause(utils.CodeDeadlock, "the transaction conflicted with another one", err)
+	case pgLockNotAvailable, pgQueryCanceled:
+		return utils.NewAppErrorWithCause(utils.CodeTimeout, "the database did not respond in time", err)
+	}
+	return err
+}
+
+func translateMySQL(err *mysql.MySQLError, resource string) error {
+	switch err.Number {
+	case mysqlDuplicateEntry:
+		field := ""
+		if m := mysqlDuplicateKey.FindStringSubmatch(err.Message); m != nil {
+			field = fieldFromConstraint(m[1], "")
+		}
+		return conflict(resource, field)
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		return utils.NewAppErrorWithCause(utils.CodeForeignKeyViolation, "a referenced record does not exist or is still referenced", err)
+	case mysqlCheckViolation:
+		return utils.NewAppErrorWithCause(utils.CodeCheckViolation, fmt.Sprintf("the %s violates a check constraint", resource), err)
+	case mysqlDeadlock:
+		return utils.NewAppErrorWithCause(utils.CodeDeadlock, "the transaction conflicted with another one", err)
+	case mysqlLockWaitTimeout, mysqlMaxExecutionTimeout:
+		return utils.NewAppErrorWithCause(utils.CodeTimeout, "the database did not respond in time", err)
+	}
+	return err
+}
+
+// conflict builds the error of a unique violation. The conflicting value
+// is left out, as it may be personal data.
+func conflict(resource, field string) error {
+	if field == "" {
+		return utils.NewConflictError(fmt.Sprintf("%s already exists", resource))
+	}
+	return utils.NewFieldConflictError(field, fmt.Sprintf("%s with this %s already exists", resource, field))
+}
+
+// fieldFromConstraint guesses the column of a unique index from its name,
+// e.g. users_email_key, idx_users_email or users.email (MySQL 8) give email
+func fieldFromConstraint(name, table string) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		table, name = name[:i], name[i+1:]
+	}
+	name = strings.TrimPrefix(name, "idx_")
+	name = strings.TrimPrefix(name, "uni_")
+	if table != "" {
+		name = strings.TrimPrefix(name, table+"_")
+	}
+	name = strings.TrimSuffix(name, "_key")
+	name = strings.TrimSuffix(name, "_unique")
+	return name
+}
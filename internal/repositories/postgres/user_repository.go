@@ -2,49 +2,212 @@ package postgres
 
 import (
 	"context"
+	"strconv"
+	"strings"
 
+	"github.com/miladev95/golang-project-structure/internal/messaging"
 	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/outbox"
 	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-// UserRepository implements the repositories.UserRepository interface
+// userResource names users in translated errors
+const userResource = "user"
+
+// Outbox topics of user changes
+const (
+	topicUserCreated = "user.created"
+	topicUserUpdated = "user.updated"
+	topicUserDeleted = "user.deleted"
+)
+
+// userEditableColumns are the columns Update writes besides the version
+var userEditableColumns = []string{"name", "email"}
+
+// userEventSchemaVersion is the payload version of user events; bump it
+// when their shape changes incompatibly
+const userEventSchemaVersion = 1
+
+// UserRepository implements the repositories.UserRepository interface.
+// Its methods join the transaction in the context, if any.
 type UserRepository struct {
 	db *gorm.DB
+	tx transaction.Manager
 }
 
 // NewUserRepository creates a new postgres user repository
 func NewUserRepository(db *gorm.DB) repositories.UserRepository {
-	return &UserRepository{db: db}
+	return &UserRepository{db: db, tx: transaction.NewManager(db)}
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
 	var user models.User
-	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
-		return nil, err
+	if err := transaction.DB(ctx, r.db).First(&user, id).Error; err != nil {
+		return nil, repositories.TranslateError(err, userResource, id)
 	}
 	return &user, nil
 }
 
-func (r *UserRepository) GetAll(ctx context.Context) ([]models.User, error) {
-	var users []models.User
-	if err := r.db.WithContext(ctx).Find(&users).Error; err != nil {
-		return nil, err
+// List counts the users matching the query filter, then loads the
+// requested page of them
+func (r *UserRepository) List(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error) {
+	order, err := userOrder(query.Sort)
+	if err != nil {
+		return nil, 0, err
+	}
+	filtered := func() *gorm.DB {
+		return filterUsers(transaction.DB(ctx, r.db).Model(&models.User{}), query.Filter)
+	}
+
+	var total int64
+	if err := filtered().Count(&total).Error; err != nil {
+		return nil, 0, repositories.TranslateError(err, userResource, nil)
+	}
+
+	users := []models.User{}
+	page := query.Pagination
+	err = filtered().
+		Clauses(order).
+		Offset(page.GetOffset()).
+		Limit(page.GetLimit()).
+		Find(&users).Error
+	if err != nil {
+		return nil, 0, repositories.TranslateError(err, userResource, nil)
+	}
+	return users, total, nil
+}
+
+// filterUsers adds the conditions of filter to db
+func filterUsers(db *gorm.DB, filter repositories.UserFilter) *gorm.DB {
+	if filter.NameContains != "" {
+		db = db.Where("LOWER(name) LIKE ?", "%"+escapeLike(strings.ToLower(filter.NameContains))+"%")
+	}
+	if filter.Email != "" {
+		db = db.Where("email = ?", filter.Email)
+	}
+	if filter.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		db = db.Where("created_at < ?", *filter.CreatedTo)
+	}
+	return db
+}
+
+// escapeLike escapes the LIKE wildcards in s
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// userOrder builds the ORDER BY of sort, ending with id so pages are
+// stable
+func userOrder(sort []utils.SortField) (clause.OrderBy, error) {
+	var order clause.OrderBy
+	byID := false
+	for _, f := range sort {
+		if !utils.IsStringInSlice(f.Field, repositories.UserSortFields) {
+			return order, utils.NewAppError(utils.CodeBadRequest, "cannot sort users by "+f.Field)
+		}
+		order.Columns = append(order.Columns, clause.OrderByColumn{Column: clause.Column{Name: f.Field}, Desc: f.Desc})
+		byID = byID || f.Field == "id"
+	}
+	if !byID {
+		order.Columns = append(order.Columns, clause.OrderByColumn{Column: clause.Column{Name: "id"}})
 	}
-	return users, nil
+	return order, nil
 }
 
+// Create inserts the user, at version 1, and its user.created event in
+// one transaction, or savepoint when called inside one
 func (r *UserRepository) Create(ctx context.Context, user *models.User) (*models.User, error) {
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
-		return nil, err
+	user.Version = 1
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := transaction.DB(ctx, r.db)
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return writeUserEvent(tx, topicUserCreated, user.ID, user)
+	})
+	if err != nil {
+		return nil, repositories.TranslateError(err, userResource, user.ID)
 	}
 	return user, nil
 }
 
+// Update saves the editable fields of the user and its user.updated event
+// in one transaction
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	return r.UpdateFields(ctx, user, userEditableColumns)
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&models.User{}, id).Error
+// UpdateFields updates the given columns, updated_at and version of the
+// user and records user.updated in one transaction. The row is only
+// updated while its version is still user.Version.
+func (r *UserRepository) UpdateFields(ctx context.Context, user *models.User, fields []string) error {
+	updated := *user
+	updated.Version++
+	columns := append([]string{"version", "updated_at"}, fields...)
+
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := transaction.DB(ctx, r.db)
+		result := tx.Model(&updated).Where("version = ?", user.Version).Select(columns).Updates(&updated)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrStale(tx, user.ID)
+		}
+		return writeUserEvent(tx, topicUserUpdated, user.ID, &updated)
+	})
+	if err != nil {
+		return repositories.TranslateError(err, userResource, user.ID)
+	}
+
+	*user = updated
+	return nil
+}
+
+// Delete removes the user, if still at version, and records user.deleted
+// in one transaction. A missing user is a not found error.
+func (r *UserRepository) Delete(ctx context.Context, id int64, version int64) error {
+	err := r.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		tx := transaction.DB(ctx, r.db)
+		result := tx.Where("version = ?", version).Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missingOrStale(tx, id)
+		}
+		return writeUserEvent(tx, topicUserDeleted, id, map[string]int64{"id": id})
+	})
+	return repositories.TranslateError(err, userResource, id)
+}
+
+// missingOrStale explains why no row of the user was affected: it is
+// missing, or another request changed its version first
+func missingOrStale(tx *gorm.DB, id int64) error {
+	var count int64
+	if err := tx.Model(&models.User{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return utils.NewFieldConflictError("version", "the user was modified by another request")
+}
+
+// writeUserEvent records a user event, wrapped in a messaging envelope, in
+// the outbox as part of tx
+func writeUserEvent(tx *gorm.DB, topic string, id int64, payload interface{}) error {
+	aggregateID := strconv.FormatInt(id, 10)
+	env, err := messaging.NewEnvelope(topic, userEventSchemaVersion, aggregateID, payload)
+	if err != nil {
+		return err
+	}
+	return outbox.Write(tx, topic, aggregateID, env)
 }
@@ -9,8 +9,17 @@ import (
 // UserRepository defines the interface for user data access
 type UserRepository interface {
 	GetByID(ctx context.Context, id int64) (*models.User, error)
-	GetAll(ctx context.Context) ([]models.User, error)
+	// List returns the page of users query selects and the number of
+	// users matching its filter
+	List(ctx context.Context, query UserQuery) ([]models.User, int64, error)
 	Create(ctx context.Context, user *models.User) (*models.User, error)
+	// Update saves the editable fields of user if its stored version is
+	// still user.Version, and increments the version. A stale version is
+	// a utils.ConflictError.
 	Update(ctx context.Context, user *models.User) error
-	Delete(ctx context.Context, id int64) error
+	// UpdateFields updates only the given columns of user, checking and
+	// incrementing the version like Update
+	UpdateFields(ctx context.Context, user *models.User, fields []string) error
+	// Delete removes the user if its stored version is still version
+	Delete(ctx context.Context, id int64, version int64) error
 }
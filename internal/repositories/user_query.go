@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"time"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// UserSortFields are the fields users can be sorted by
+var UserSortFields = []string{"id", "name", "email", "created_at", "updated_at"}
+
+// UserFilter narrows a user query; zero fields don't filter
+type UserFilter struct {
+	// NameContains matches names containing it, ignoring case
+	NameContains string
+	// Email matches the email exactly
+	Email string
+	// CreatedFrom and CreatedTo bound created_at, from inclusive and to
+	// exclusive
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// UserQuery selects a page of users. Sort fields come from UserSortFields;
+// ties, and queries without sort, are ordered by id.
+type UserQuery struct {
+	Pagination utils.Pagination
+	Sort       []utils.SortField
+	Filter     UserFilter
+}
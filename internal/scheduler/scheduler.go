@@ -0,0 +1,219 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Options configures a Scheduler
+type Options struct {
+	// LeaseTTL bounds how long a crashed instance blocks a task. It should
+	// exceed the longest expected run.
+	LeaseTTL time.Duration
+	// Location is the time zone cron expressions are evaluated in
+	Location *time.Location
+}
+
+// entry is a registered task with its parsed schedule
+type entry struct {
+	Registration
+	schedule cron.Schedule
+
+	mu      sync.Mutex
+	running bool
+}
+
+// Scheduler runs registered tasks on their cron schedules
+type Scheduler struct {
+	store   Store
+	entries []*entry
+	opts    Options
+	holder  string
+}
+
+// parser accepts standard five-field expressions and descriptors
+var parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// New creates a scheduler; it fails on duplicate names or invalid schedules
+func New(store Store, registrations []Registration, opts Options) (*Scheduler, error) {
+	if opts.LeaseTTL <= 0 {
+		opts.LeaseTTL = 10 * time.Minute
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	seen := make(map[string]bool, len(registrations))
+	entries := make([]*entry, 0, len(registrations))
+	for _, r := range registrations {
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate scheduled task %q", r.Name)
+		}
+		seen[r.Name] = true
+
+		schedule, err := parser.Parse(r.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q for task %q: %w", r.Schedule, r.Name, err)
+		}
+		entries = append(entries, &entry{Registration: r, schedule: schedule})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		store:   store,
+		entries: entries,
+		opts:    opts,
+		holder:  fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}, nil
+}
+
+// next returns the first tick of the task's schedule after t. @every
+// schedules are aligned to multiples of their interval so that every
+// replica computes the same ticks.
+func (e *entry) next(t time.Time) time.Time {
+	if every, ok := e.schedule.(cron.ConstantDelaySchedule); ok {
+		return t.Truncate(every.Delay).Add(every.Delay)
+	}
+	return e.schedule.Next(t)
+}
+
+// Run schedules every task until ctx is cancelled, then waits for running
+// tasks to finish
+func (s *Scheduler) Run(ctx context.Context) error {
+	names := make([]string, len(s.entries))
+	for i, e := range s.entries {
+		names[i] = e.Name
+	}
+	if err := s.store.Ensure(ctx, names); err != nil {
+		return fmt.Errorf("failed to register scheduled tasks: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, e := range s.entries {
+		wg.Add(1)
+		go func(e *entry) {
+			defer wg.Done()
+			s.loop(ctx, e)
+		}(e)
+	}
+	wg.Wait()
+	return nil
+}
+
+func (s *Scheduler) loop(ctx context.Context, e *entry) {
+	for {
+		tick := e.next(time.Now().In(s.opts.Location))
+		timer := time.NewTimer(time.Until(tick))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if _, err := s.RunDue(ctx, e.Name, tick); err != nil {
+			log.Printf("scheduler: task %s: %v", e.Name, err)
+		}
+	}
+}
+
+// RunDue runs the task for the given tick if this instance wins the lease.
+// It reports whether the task ran; the task's own error is recorded in the
+// store, not returned.
+func (s *Scheduler) RunDue(ctx context.Context, name string, tick time.Time) (bool, error) {
+	e := s.entry(name)
+	if e == nil {
+		return false, fmt.Errorf("unknown task %q", name)
+	}
+
+	acquired, err := s.store.Acquire(ctx, name, tick, s.holder, s.opts.LeaseTTL)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire lease: %w", err)
+	}
+	if !acquired {
+		return false, nil
+	}
+
+	e.mu.Lock()
+	e.running = true
+	e.mu.Unlock()
+
+	startedAt := time.Now()
+	runErr := s.run(ctx, e)
+	duration := time.Since(startedAt)
+
+	e.mu.Lock()
+	e.running = false
+	e.mu.Unlock()
+
+	if runErr != nil {
+		log.Printf("scheduler: task %s failed after %v: %v", name, duration, runErr)
+	}
+
+	// Record the run even when shutdown cancelled ctx mid-run
+	if err := s.store.Release(context.WithoutCancel(ctx), name, s.holder, startedAt, duration, runErr); err != nil {
+		return true, fmt.Errorf("failed to record run: %w", err)
+	}
+	return true, nil
+}
+
+// run runs the task, turning a panic into an error
+func (s *Scheduler) run(ctx context.Context, e *entry) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return e.Task.Run(ctx)
+}
+
+// Tasks returns the registered tasks with their persisted state
+func (s *Scheduler) Tasks(ctx context.Context) ([]TaskStatus, error) {
+	states, err := s.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]TaskState, len(states))
+	for _, state := range states {
+		byName[state.Name] = state
+	}
+
+	now := time.Now().In(s.opts.Location)
+	statuses := make([]TaskStatus, 0, len(s.entries))
+	for _, e := range s.entries {
+		state, ok := byName[e.Name]
+		if !ok {
+			state = TaskState{Name: e.Name}
+		}
+
+		e.mu.Lock()
+		running := e.running
+		e.mu.Unlock()
+
+		statuses = append(statuses, TaskStatus{
+			TaskState: state,
+			Schedule:  e.Schedule,
+			NextRunAt: e.next(now),
+			Running:   running,
+		})
+	}
+	return statuses, nil
+}
+
+func (s *Scheduler) entry(name string) *entry {
+	for _, e := range s.entries {
+		if e.Name == name {
+			return e
+		}
+	}
+	return nil
+}
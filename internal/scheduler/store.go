@@ -0,0 +1,81 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Store persists task leases and run results
+type Store interface {
+	// Ensure creates the state rows of tasks that don't have one yet
+	Ensure(ctx context.Context, names []string) error
+	// Acquire claims the run scheduled at tick for holder. It fails, without
+	// an error, when another replica already claimed that tick or still
+	// holds an unexpired lease.
+	Acquire(ctx context.Context, name string, tick time.Time, holder string, ttl time.Duration) (bool, error)
+	// Release records the outcome of holder's run and ends its lease
+	Release(ctx context.Context, name, holder string, startedAt time.Time, duration time.Duration, runErr error) error
+	// List returns the state of every task
+	List(ctx context.Context) ([]TaskState, error)
+}
+
+// gormStore is a Store on the scheduler_tasks table
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewStore creates a Store backed by the database
+func NewStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Ensure(ctx context.Context, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	states := make([]TaskState, len(names))
+	for i, name := range names {
+		states[i] = TaskState{Name: name}
+	}
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&states).Error
+}
+
+// Acquire is a single conditional UPDATE, so exactly one replica wins
+func (s *gormStore) Acquire(ctx context.Context, name string, tick time.Time, holder string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	result := s.db.WithContext(ctx).Model(&TaskState{}).
+		Where("name = ?", name).
+		Where("last_scheduled_at IS NULL OR last_scheduled_at < ?", tick).
+		Where("lease_until IS NULL OR lease_until < ?", now).
+		Updates(map[string]interface{}{
+			"last_scheduled_at": tick,
+			"lease_holder":      holder,
+			"lease_until":       now.Add(ttl),
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (s *gormStore) Release(ctx context.Context, name, holder string, startedAt time.Time, duration time.Duration, runErr error) error {
+	lastError := ""
+	if runErr != nil {
+		lastError = runErr.Error()
+	}
+	return s.db.WithContext(ctx).Model(&TaskState{}).
+		Where("name = ? AND lease_holder = ?", name, holder).
+		Updates(map[string]interface{}{
+			"lease_holder":     "",
+			"lease_until":      nil,
+			"last_run_at":      startedAt,
+			"last_duration_ms": duration.Milliseconds(),
+			"last_error":       lastError,
+		}).Error
+}
+
+func (s *gormStore) List(ctx context.Context) ([]TaskState, error) {
+	var states []TaskState
+	err := s.db.WithContext(ctx).Order("name").Find(&states).Error
+	return states, err
+}
@@ -0,0 +1,65 @@
+// Package scheduler runs cron-style periodic tasks registered by modules.
+// Replicas coordinate through a lease row per task, so each scheduled run
+// happens on exactly one instance.
+package scheduler
+
+import (
+	"context"
+	"time"
+)
+
+// Group is the dig value group modules provide Registrations into
+const Group = "scheduler.tasks"
+
+// Task is periodic work
+type Task interface {
+	Run(ctx context.Context) error
+}
+
+// TaskFunc adapts a function to the Task interface
+type TaskFunc func(ctx context.Context) error
+
+// Run calls f
+func (f TaskFunc) Run(ctx context.Context) error {
+	return f(ctx)
+}
+
+// Registration is what a module provides to schedule a task. Schedule is a
+// standard five-field cron expression or a descriptor such as @hourly or
+// @every 10m:
+//
+//	container.Provide(func(repo TokenRepository) scheduler.Registration {
+//		return scheduler.Registration{Name: "purge_expired_tokens", Schedule: "0 * * * *", Task: ...}
+//	}, dig.Group(scheduler.Group))
+type Registration struct {
+	Name     string
+	Schedule string
+	Task     Task
+}
+
+// TaskState is the persisted state of a task: its lease and last run
+type TaskState struct {
+	Name string `json:"name" gorm:"primaryKey;size:255"`
+	// LastScheduledAt is the latest tick claimed by any replica
+	LastScheduledAt *time.Time `json:"last_scheduled_at,omitempty"`
+	LeaseHolder     string     `json:"lease_holder,omitempty" gorm:"size:255"`
+	LeaseUntil      *time.Time `json:"lease_until,omitempty"`
+	LastRunAt       *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMs  int64      `json:"last_duration_ms"`
+	LastError       string     `json:"last_error,omitempty" gorm:"type:text"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName returns the scheduler table name
+func (TaskState) TableName() string {
+	return "scheduler_tasks"
+}
+
+// TaskStatus describes a registered task for the admin API
+type TaskStatus struct {
+	TaskState
+	Schedule  string    `json:"schedule"`
+	NextRunAt time.Time `json:"next_run_at"`
+	// Running is true while this instance is running the task
+	Running bool `json:"running"`
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+)
+
+// Server is an HTTP server together with the network it listens on
+type Server struct {
+	*http.Server
+	// Network is "tcp", or "unix" when Addr is a socket path
+	Network string
+}
+
+// NewPublic creates the public HTTP server. It listens on a Unix socket when
+// one is configured, and serves TLS or h2c when enabled.
+func NewPublic(cfg *config.Config, handler http.Handler) (*Server, error) {
+	srv := &Server{
+		Server: &http.Server{
+			Addr:              cfg.Server.Host + ":" + cfg.Server.Port,
+			Handler:           handler,
+			ReadTimeout:       cfg.Server.ReadTimeout,
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+			WriteTimeout:      cfg.Server.WriteTimeout,
+			IdleTimeout:       cfg.Server.IdleTimeout,
+			MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		},
+		Network: "tcp",
+	}
+
+	if cfg.Server.Socket != "" {
+		srv.Addr = cfg.Server.Socket
+		srv.Network = "unix"
+	}
+
+	if cfg.Server.H2C {
+		srv.Handler = h2c.NewHandler(handler, &http2.Server{
+			IdleTimeout: cfg.Server.IdleTimeout,
+		})
+	}
+
+	if cfg.TLSEnabled() {
+		tlsConfig, err := NewTLSConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
+	}
+
+	return srv, nil
+}
+
+// NewAdmin creates the plaintext HTTP server of the admin listener. It has
+// no write timeout so CPU profiles and traces can run for their full duration.
+func NewAdmin(cfg *config.Config, handler http.Handler) *Server {
+	return &Server{
+		Server: &http.Server{
+			Addr:              cfg.Server.AdminHost + ":" + cfg.Server.AdminPort,
+			Handler:           handler,
+			ReadTimeout:       cfg.Server.ReadTimeout,
+			ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+			IdleTimeout:       cfg.Server.IdleTimeout,
+			MaxHeaderBytes:    cfg.Server.MaxHeaderBytes,
+		},
+		Network: "tcp",
+	}
+}
+
+// ListenAndServe listens on the server's address and serves, over TLS when
+// it has a TLS configuration
+func (s *Server) ListenAndServe() error {
+	listener, err := s.Listen()
+	if err != nil {
+		return err
+	}
+	return s.Serve(listener)
+}
+
+// Listen opens the server's listener. A stale socket file left by a previous
+// process is removed first.
+func (s *Server) Listen() (net.Listener, error) {
+	if s.Network == "unix" {
+		if err := os.Remove(s.Addr); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale socket %s: %w", s.Addr, err)
+		}
+	}
+	return net.Listen(s.Network, s.Addr)
+}
+
+// Serve accepts connections on listener. Certificates come from the TLS
+// configuration, not from files.
+func (s *Server) Serve(listener net.Listener) error {
+	if s.TLSConfig != nil {
+		return s.Server.ServeTLS(listener, "", "")
+	}
+	return s.Server.Serve(listener)
+}
@@ -0,0 +1,265 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+)
+
+// reloadCheckInterval is how often reloaders look at their files; handshakes
+// in between reuse the last result
+const reloadCheckInterval = time.Second
+
+// fileWatch reports changes to the newest modification time of files,
+// statting them at most once per interval
+type fileWatch struct {
+	files []string
+
+	mu        sync.Mutex
+	interval  time.Duration
+	lastCheck time.Time
+	modTime   time.Time
+}
+
+func newFileWatch(files ...string) *fileWatch {
+	return &fileWatch{files: files, interval: reloadCheckInterval}
+}
+
+// setInterval changes how often the files are checked
+func (w *fileWatch) setInterval(interval time.Duration) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.interval = interval
+}
+
+// poll returns the newest modification time and whether it differs from the
+// last loaded one. It reports no change within interval of the last check.
+func (w *fileWatch) poll() (time.Time, bool) {
+	w.mu.Lock()
+	now := time.Now()
+	if now.Sub(w.lastCheck) < w.interval {
+		w.mu.Unlock()
+		return time.Time{}, false
+	}
+	w.lastCheck = now
+	loaded := w.modTime
+	w.mu.Unlock()
+
+	modTime, err := latestModTime(w.files)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return modTime, !modTime.Equal(loaded)
+}
+
+// loaded records the modification time of the files last loaded
+func (w *fileWatch) loaded(modTime time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.modTime = modTime
+}
+
+// latestModTime returns the newest modification time of files
+func latestModTime(files []string) (time.Time, error) {
+	var latest time.Time
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest, nil
+}
+
+// CertReloader serves a certificate key pair and reloads it when either
+// file changes on disk, so rotated certificates apply without a restart.
+// The files are checked at most once per second.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+	watch    *fileWatch
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertReloader loads the key pair; it fails if the files can't be loaded
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile, watch: newFileWatch(certFile, keyFile)}
+
+	modTime, err := latestModTime(r.watch.files)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetCheckInterval changes how often the files are checked for changes
+func (r *CertReloader) SetCheckInterval(interval time.Duration) {
+	r.watch.setInterval(interval)
+}
+
+// GetCertificate implements tls.Config.GetCertificate. A failed reload,
+// e.g. while files are half written, keeps serving the previous certificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if modTime, changed := r.watch.poll(); changed {
+		if err := r.load(modTime); err != nil {
+			log.Printf("Failed to reload TLS certificate, keeping previous: %v", err)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+func (r *CertReloader) load(modTime time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	r.watch.loaded(modTime)
+	return nil
+}
+
+// ClientCAReloader serves the pool of client CAs and reloads it when the
+// bundle changes on disk, checking at most once per second
+type ClientCAReloader struct {
+	file  string
+	watch *fileWatch
+
+	mu   sync.RWMutex
+	pool *x509.CertPool
+}
+
+// NewClientCAReloader loads the bundle; it fails if it holds no certificates
+func NewClientCAReloader(file string) (*ClientCAReloader, error) {
+	r := &ClientCAReloader{file: file, watch: newFileWatch(file)}
+
+	modTime, err := latestModTime(r.watch.files)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(modTime); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// SetCheckInterval changes how often the bundle is checked for changes
+func (r *ClientCAReloader) SetCheckInterval(interval time.Duration) {
+	r.watch.setInterval(interval)
+}
+
+// Pool returns the current client CAs. A failed reload keeps the previous
+// pool.
+func (r *ClientCAReloader) Pool() *x509.CertPool {
+	if modTime, changed := r.watch.poll(); changed {
+		if err := r.load(modTime); err != nil {
+			log.Printf("Failed to reload client CA bundle, keeping previous: %v", err)
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.pool
+}
+
+// configForClient returns a tls.Config.GetConfigForClient verifying client
+// certificates against the current pool. The returned configs copy base,
+// which net/http never sees, so they advertise HTTP/2 themselves as
+// http.Server.ServeTLS would.
+func (r *ClientCAReloader) configForClient(base *tls.Config) func(*tls.ClientHelloInfo) (*tls.Config, error) {
+	base = base.Clone()
+	if len(base.NextProtos) == 0 {
+		base.NextProtos = []string{"h2", "http/1.1"}
+	}
+
+	var (
+		mu     sync.Mutex
+		pool   *x509.CertPool
+		config *tls.Config
+	)
+	return func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		current := r.Pool()
+
+		mu.Lock()
+		defer mu.Unlock()
+		if current != pool {
+			config = base.Clone()
+			config.ClientCAs = current
+			pool = current
+		}
+		return config, nil
+	}
+}
+
+func (r *ClientCAReloader) load(modTime time.Time) error {
+	pem, err := os.ReadFile(r.file)
+	if err != nil {
+		return fmt.Errorf("failed to read client CA bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no certificates found in client CA bundle %s", r.file)
+	}
+
+	r.mu.Lock()
+	r.pool = pool
+	r.mu.Unlock()
+	r.watch.loaded(modTime)
+	return nil
+}
+
+// NewTLSConfig builds the TLS configuration of the public listener
+func NewTLSConfig(cfg *config.Config) (*tls.Config, error) {
+	minVersion, err := config.ParseTLSVersion(cfg.TLS.MinVersion)
+	if err != nil {
+		return nil, err
+	}
+	cipherSuites, err := config.ParseCipherSuites(cfg.TLS.CipherSuites)
+	if err != nil {
+		return nil, err
+	}
+
+	reloader, err := NewCertReloader(cfg.TLS.CertFile, cfg.TLS.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:     minVersion,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if len(cipherSuites) > 0 {
+		tlsConfig.CipherSuites = cipherSuites
+	}
+
+	if cfg.MTLSEnabled() {
+		caReloader, err := NewClientCAReloader(cfg.TLS.ClientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = caReloader.Pool()
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		tlsConfig.GetConfigForClient = caReloader.configForClient(tlsConfig)
+	}
+
+	return tlsConfig, nil
+}
@@ -2,29 +2,47 @@ package services
 
 import (
 	"context"
+	"log"
 
+	"github.com/miladev95/golang-project-structure/internal/events"
 	"github.com/miladev95/golang-project-structure/internal/models"
 	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
 )
 
 // UserService defines the business logic interface for users
 type UserService interface {
 	GetUser(ctx context.Context, id int64) (*models.User, error)
-	GetAllUsers(ctx context.Context) ([]models.User, error)
+	// ListUsers returns the page of users query selects and the number of
+	// users matching its filter
+	ListUsers(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error)
 	CreateUser(ctx context.Context, user *models.User) (*models.User, error)
+	// UpdateUser saves user if its stored version is still user.Version;
+	// a zero version overwrites any version
 	UpdateUser(ctx context.Context, user *models.User) error
-	DeleteUser(ctx context.Context, id int64) error
+	// PatchUser applies patch to the stored user and saves the fields it
+	// changed, returning the user and those fields. Like the version of
+	// UpdateUser, a non-zero version must match the stored one.
+	PatchUser(ctx context.Context, id int64, version int64, patch func(user *models.User) error) (*models.User, []string, error)
+	// DeleteUser removes the user; a non-zero version must match the
+	// stored one
+	DeleteUser(ctx context.Context, id int64, version int64) error
 }
 
 // userService implements UserService
 type userService struct {
 	userRepo repositories.UserRepository
+	tx       transaction.Manager
+	bus      *events.Bus
 }
 
-// NewUserService creates a new user service
-func NewUserService(userRepo repositories.UserRepository) UserService {
+// NewUserService creates a new user service that publishes UserCreated,
+// UserUpdated and UserDeleted to bus
+func NewUserService(userRepo repositories.UserRepository, tx transaction.Manager, bus *events.Bus) UserService {
 	return &userService{
 		userRepo: userRepo,
+		tx:       tx,
+		bus:      bus,
 	}
 }
 
@@ -32,20 +50,105 @@ func (s *userService) GetUser(ctx context.Context, id int64) (*models.User, erro
 	return s.userRepo.GetByID(ctx, id)
 }
 
-func (s *userService) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	return s.userRepo.GetAll(ctx)
+func (s *userService) ListUsers(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error) {
+	return s.userRepo.List(ctx, query)
 }
 
 func (s *userService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
 	// Add business logic here (validation, etc.)
-	return s.userRepo.Create(ctx, user)
+	created, err := s.userRepo.Create(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, UserCreated{User: *created})
+	return created, nil
 }
 
 func (s *userService) UpdateUser(ctx context.Context, user *models.User) error {
 	// Add business logic here
-	return s.userRepo.Update(ctx, user)
+	var changed []string
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.userRepo.GetByID(ctx, user.ID)
+		if err != nil {
+			return err
+		}
+		changed = changedUserFields(before, user)
+		// Callers only set the editable fields
+		user.CreatedAt = before.CreatedAt
+		if user.Version == 0 {
+			user.Version = before.Version
+		}
+		return s.userRepo.Update(ctx, user)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, UserUpdated{User: *user, Changed: changed})
+	return nil
 }
 
-func (s *userService) DeleteUser(ctx context.Context, id int64) error {
-	return s.userRepo.Delete(ctx, id)
+func (s *userService) PatchUser(ctx context.Context, id int64, version int64, patch func(user *models.User) error) (*models.User, []string, error) {
+	var (
+		user    *models.User
+		changed []string
+	)
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		patched := *before
+		if err := patch(&patched); err != nil {
+			return err
+		}
+		// The patch may only touch the editable fields
+		patched.ID, patched.CreatedAt, patched.UpdatedAt = before.ID, before.CreatedAt, before.UpdatedAt
+		patched.Version = before.Version
+		if version != 0 {
+			patched.Version = version
+		}
+
+		user, changed = &patched, changedUserFields(before, &patched)
+		if len(changed) == 0 {
+			return nil
+		}
+		return s.userRepo.UpdateFields(ctx, user, changed)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(changed) > 0 {
+		s.publish(ctx, UserUpdated{User: *user, Changed: changed})
+	}
+	return user, changed, nil
+}
+
+func (s *userService) DeleteUser(ctx context.Context, id int64, version int64) error {
+	err := s.tx.WithinTransaction(ctx, func(ctx context.Context) error {
+		if version == 0 {
+			user, err := s.userRepo.GetByID(ctx, id)
+			if err != nil {
+				return err
+			}
+			version = user.Version
+		}
+		return s.userRepo.Delete(ctx, id, version)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, UserDeleted{ID: id})
+	return nil
+}
+
+// publish notifies subscribers once the change is stored. Subscriber
+// failures are logged: the change itself already succeeded.
+func (s *userService) publish(ctx context.Context, event events.Event) {
+	if err := s.bus.Publish(ctx, event); err != nil {
+		log.Printf("failed to publish %s: %v", event.EventName(), err)
+	}
 }
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"strconv"
+
+	"github.com/miladev95/golang-project-structure/internal/models"
+)
+
+// UserCreated is published after a user is created
+type UserCreated struct {
+	User models.User
+}
+
+// EventName returns the event name
+func (e UserCreated) EventName() string { return "user.created" }
+
+// AggregateID returns the user ID
+func (e UserCreated) AggregateID() string { return strconv.FormatInt(e.User.ID, 10) }
+
+// UserUpdated is published after a user is updated
+type UserUpdated struct {
+	User models.User
+	// Changed lists the JSON names of the fields that changed
+	Changed []string
+}
+
+// EventName returns the event name
+func (e UserUpdated) EventName() string { return "user.updated" }
+
+// AggregateID returns the user ID
+func (e UserUpdated) AggregateID() string { return strconv.FormatInt(e.User.ID, 10) }
+
+// UserDeleted is published after a user is deleted
+type UserDeleted struct {
+	ID int64
+}
+
+// EventName returns the event name
+func (e UserDeleted) EventName() string { return "user.deleted" }
+
+// AggregateID returns the user ID
+func (e UserDeleted) AggregateID() string { return strconv.FormatInt(e.ID, 10) }
+
+// changedUserFields returns the JSON names of the fields that differ
+func changedUserFields(before, after *models.User) []string {
+	var changed []string
+	if before.Name != after.Name {
+		changed = append(changed, "name")
+	}
+	if before.Email != after.Email {
+		changed = append(changed, "email")
+	}
+	return changed
+}
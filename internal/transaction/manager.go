@@ -0,0 +1,59 @@
+// Package transaction lets services make several repository calls atomic.
+// The transaction travels in the context; repositories get it with DB.
+package transaction
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Manager runs functions inside a database transaction
+type Manager interface {
+	// WithinTransaction runs fn in a transaction that commits when fn
+	// returns nil and rolls back when it returns an error or panics. Called
+	// inside another transaction, it runs fn in a savepoint, so a failure
+	// only undoes fn's changes.
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// txKey is the context key of the current transaction
+type txKey struct{}
+
+// gormManager is a Manager on a gorm database
+type gormManager struct {
+	db *gorm.DB
+}
+
+// NewManager creates a Manager on db
+func NewManager(db *gorm.DB) Manager {
+	return &gormManager{db: db}
+}
+
+func (m *gormManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	// gorm uses a savepoint when the handle is already a transaction and
+	// rolls back to it, or the whole transaction, on panic
+	return DB(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(NewContext(ctx, tx))
+	})
+}
+
+// NewContext returns a copy of ctx carrying tx
+func NewContext(ctx context.Context, tx *gorm.DB) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// FromContext returns the transaction in ctx, if any
+func FromContext(ctx context.Context) (*gorm.DB, bool) {
+	tx, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return tx, ok
+}
+
+// DB returns the transaction in ctx, or db when there is none, bound to ctx.
+// Repositories use it instead of db.WithContext(ctx).
+func DB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := FromContext(ctx); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
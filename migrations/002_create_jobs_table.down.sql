@@ -0,0 +1,2 @@
+-- Drop table (drops its index too)
+DROP TABLE IF EXISTS jobs;
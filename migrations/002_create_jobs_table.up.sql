@@ -0,0 +1,18 @@
+-- Create jobs table for the background job queue
+CREATE TABLE IF NOT EXISTS jobs (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    payload TEXT,
+    status VARCHAR(16) NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    max_attempts BIGINT NOT NULL,
+    run_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT,
+    locked_by VARCHAR(255),
+    locked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+-- Workers poll for due jobs by status and run time
+CREATE INDEX IF NOT EXISTS idx_jobs_status_run_at ON jobs(status, run_at);
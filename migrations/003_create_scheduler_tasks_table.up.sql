@@ -0,0 +1,11 @@
+-- Create scheduler_tasks table for cron task leases and last runs
+CREATE TABLE IF NOT EXISTS scheduler_tasks (
+    name VARCHAR(255) PRIMARY KEY,
+    last_scheduled_at TIMESTAMPTZ,
+    lease_holder VARCHAR(255),
+    lease_until TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    last_duration_ms BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    updated_at TIMESTAMPTZ
+);
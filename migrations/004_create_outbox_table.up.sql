@@ -0,0 +1,16 @@
+-- Create outbox table for the transactional outbox
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    topic VARCHAR(255) NOT NULL,
+    aggregate_id VARCHAR(255),
+    payload TEXT,
+    status VARCHAR(16) NOT NULL,
+    attempts BIGINT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMPTZ,
+    sent_at TIMESTAMPTZ
+);
+
+-- The relay claims due messages by status and next attempt
+CREATE INDEX IF NOT EXISTS idx_outbox_status_next_attempt_at ON outbox(status, next_attempt_at);
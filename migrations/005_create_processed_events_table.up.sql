@@ -0,0 +1,7 @@
+-- Create processed_events table; one row per event a consumer group handled
+CREATE TABLE IF NOT EXISTS processed_events (
+    consumer VARCHAR(255) NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    processed_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (consumer, event_id)
+);
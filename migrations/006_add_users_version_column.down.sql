@@ -0,0 +1,2 @@
+-- Drop version column
+ALTER TABLE users DROP COLUMN IF EXISTS version;
@@ -0,0 +1,2 @@
+-- Add version column for optimistic concurrency (ETag / If-Match)
+ALTER TABLE users ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"math/rand"
+	"time"
+)
+
+// ExponentialBackoff returns a retry delay function that doubles the delay
+// after every attempt, starting at base and capped at max, with up to 20%
+// jitter so retries don't line up
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		delay := base
+		for i := 1; i < attempt && delay < max; i++ {
+			delay *= 2
+		}
+		if delay > max {
+			delay = max
+		}
+		return delay + time.Duration(rand.Int63n(int64(delay)/5+1))
+	}
+}
@@ -0,0 +1,83 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+)
+
+// Error codes of the typed errors in this package
+const (
+	CodeValidation   = "VALIDATION_ERROR"
+	CodeNotFound     = "NOT_FOUND"
+	CodeConflict     = "CONFLICT"
+	CodeUnauthorized = "UNAUTHORIZED"
+	CodeForbidden    = "FORBIDDEN"
+	CodeInternal     = "INTERNAL_ERROR"
+)
+
+// Error codes of client errors without a dedicated error type
+const (
+	CodeBadRequest           = "BAD_REQUEST"
+	CodeTooManyRequests      = "TOO_MANY_REQUESTS"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodePreconditionFailed   = "PRECONDITION_FAILED"
+)
+
+// Error codes of database failures other than not found and conflicts
+const (
+	CodeForeignKeyViolation = "FOREIGN_KEY_VIOLATION"
+	CodeCheckViolation      = "CHECK_VIOLATION"
+	// CodeDeadlock means the transaction was aborted and can be retried
+	CodeDeadlock = "DEADLOCK"
+	CodeTimeout  = "TIMEOUT"
+)
+
+// ErrorCode describes an error code in the catalog
+type ErrorCode struct {
+	Code string
+	// Status is the HTTP status errors with the code are answered with
+	Status int
+	// Message is shown when an error with the code has none
+	Message string
+}
+
+var (
+	codesMu sync.RWMutex
+	codes   = map[string]ErrorCode{}
+)
+
+func init() {
+	for _, c := range []ErrorCode{
+		{CodeValidation, http.StatusUnprocessableEntity, "validation failed"},
+		{CodeNotFound, http.StatusNotFound, "resource not found"},
+		{CodeConflict, http.StatusConflict, "resource already exists"},
+		{CodeUnauthorized, http.StatusUnauthorized, "authentication required"},
+		{CodeForbidden, http.StatusForbidden, "access denied"},
+		{CodeInternal, http.StatusInternalServerError, "internal server error"},
+		{CodeBadRequest, http.StatusBadRequest, "bad request"},
+		{CodeTooManyRequests, http.StatusTooManyRequests, "too many requests"},
+		{CodeUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported media type"},
+		{CodePreconditionFailed, http.StatusPreconditionFailed, "the resource has changed since it was read"},
+		{CodeForeignKeyViolation, http.StatusConflict, "a referenced record does not exist or is still referenced"},
+		{CodeCheckViolation, http.StatusUnprocessableEntity, "the record violates a constraint"},
+		{CodeDeadlock, http.StatusServiceUnavailable, "the request conflicted with another one, please retry"},
+		{CodeTimeout, http.StatusServiceUnavailable, "the request timed out, please retry"},
+	} {
+		RegisterErrorCode(c)
+	}
+}
+
+// RegisterErrorCode adds code to the catalog, replacing an existing entry
+func RegisterErrorCode(code ErrorCode) {
+	codesMu.Lock()
+	defer codesMu.Unlock()
+	codes[code.Code] = code
+}
+
+// LookupErrorCode returns the catalog entry of code
+func LookupErrorCode(code string) (ErrorCode, bool) {
+	codesMu.RLock()
+	defer codesMu.RUnlock()
+	c, ok := codes[code]
+	return c, ok
+}
@@ -10,6 +10,8 @@ type AppError struct {
 	Message string
 	Details interface{}
 	Err     error
+	// Stack is where the error was created, when stack capture is enabled
+	Stack Stack
 }
 
 func (e *AppError) Error() string {
@@ -19,11 +21,30 @@ func (e *AppError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// Unwrap returns the cause
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is an AppError with the same code, so
+// errors.Is(err, utils.NewAppError(utils.CodeTimeout, "")) matches any
+// timeout
+func (e *AppError) Is(target error) bool {
+	t, ok := target.(*AppError)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// Format prints the cause chain and the stack with %+v
+func (e *AppError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, e, e.Stack, e.Err)
+}
+
 // NewAppError creates a new app error
 func NewAppError(code, message string) *AppError {
 	return &AppError{
 		Code:    code,
 		Message: message,
+		Stack:   callers(),
 	}
 }
 
@@ -33,6 +54,7 @@ func NewAppErrorWithCause(code, message string, err error) *AppError {
 		Code:    code,
 		Message: message,
 		Err:     err,
+		Stack:   callers(),
 	}
 }
 
@@ -53,6 +75,11 @@ func (e ValidationError) Error() string {
 	return fmt.Sprintf("validation error on field '%s': %s", e.Field, e.Message)
 }
 
+// As lets errors.As match the error by value and by pointer
+func (e ValidationError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
 // ValidationErrors is a collection of validation errors
 type ValidationErrors struct {
 	Code   string             `json:"code"`
@@ -63,10 +90,15 @@ func (ve ValidationErrors) Error() string {
 	return fmt.Sprintf("validation error: %d field(s) failed validation", len(ve.Errors))
 }
 
+// As lets errors.As match the error by value and by pointer
+func (ve ValidationErrors) As(target interface{}) bool {
+	return asValue(ve, target)
+}
+
 // NewValidationErrors creates a new validation errors collection
 func NewValidationErrors() *ValidationErrors {
 	return &ValidationErrors{
-		Code:   "VALIDATION_ERROR",
+		Code:   CodeValidation,
 		Errors: []ValidationError{},
 	}
 }
@@ -105,6 +137,11 @@ func (e NotFoundError) Error() string {
 	return fmt.Sprintf("%s with id %v not found", e.Resource, e.ID)
 }
 
+// As lets errors.As match the error by value and by pointer
+func (e NotFoundError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
 // NewNotFoundError creates a new not found error
 func NewNotFoundError(resource string, id interface{}) NotFoundError {
 	return NotFoundError{
@@ -116,12 +153,19 @@ func NewNotFoundError(resource string, id interface{}) NotFoundError {
 // ConflictError represents a conflict error (e.g., duplicate entry)
 type ConflictError struct {
 	Message string
+	// Field names the conflicting field, when known
+	Field string
 }
 
 func (e ConflictError) Error() string {
 	return fmt.Sprintf("conflict: %s", e.Message)
 }
 
+// As lets errors.As match the error by value and by pointer
+func (e ConflictError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
 // NewConflictError creates a new conflict error
 func NewConflictError(message string) ConflictError {
 	return ConflictError{
@@ -129,6 +173,14 @@ func NewConflictError(message string) ConflictError {
 	}
 }
 
+// NewFieldConflictError creates a conflict error on field
+func NewFieldConflictError(field, message string) ConflictError {
+	return ConflictError{
+		Message: message,
+		Field:   field,
+	}
+}
+
 // UnauthorizedError represents an unauthorized error
 type UnauthorizedError struct {
 	Message string
@@ -138,6 +190,11 @@ func (e UnauthorizedError) Error() string {
 	return fmt.Sprintf("unauthorized: %s", e.Message)
 }
 
+// As lets errors.As match the error by value and by pointer
+func (e UnauthorizedError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
 // NewUnauthorizedError creates a new unauthorized error
 func NewUnauthorizedError(message string) UnauthorizedError {
 	return UnauthorizedError{
@@ -154,6 +211,11 @@ func (e ForbiddenError) Error() string {
 	return fmt.Sprintf("forbidden: %s", e.Message)
 }
 
+// As lets errors.As match the error by value and by pointer
+func (e ForbiddenError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
 // NewForbiddenError creates a new forbidden error
 func NewForbiddenError(message string) ForbiddenError {
 	return ForbiddenError{
@@ -165,6 +227,8 @@ func NewForbiddenError(message string) ForbiddenError {
 type InternalServerError struct {
 	Message string
 	Err     error
+	// Stack is where the error was created, when stack capture is enabled
+	Stack Stack
 }
 
 func (e InternalServerError) Error() string {
@@ -174,10 +238,41 @@ func (e InternalServerError) Error() string {
 	return fmt.Sprintf("internal server error: %s", e.Message)
 }
 
+// Unwrap returns the cause
+func (e InternalServerError) Unwrap() error {
+	return e.Err
+}
+
+// As lets errors.As match the error by value and by pointer
+func (e InternalServerError) As(target interface{}) bool {
+	return asValue(e, target)
+}
+
+// Format prints the cause chain and the stack with %+v
+func (e InternalServerError) Format(s fmt.State, verb rune) {
+	formatError(s, verb, e, e.Stack, e.Err)
+}
+
 // NewInternalServerError creates a new internal server error
 func NewInternalServerError(message string, err error) InternalServerError {
 	return InternalServerError{
 		Message: message,
 		Err:     err,
+		Stack:   callers(),
 	}
-}
\ No newline at end of file
+}
+
+// asValue implements As for the value error types: errors.As finds a T
+// stored by value for a *T target, and a *T stored in the chain for a T
+// target
+func asValue[T error](err T, target interface{}) bool {
+	switch t := target.(type) {
+	case **T:
+		*t = &err
+		return true
+	case *T:
+		*t = err
+		return true
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SortField orders results by Field, descending when Desc
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort parses a comma separated sort parameter such as
+// "-created_at,name", where a leading "-" sorts descending
+func ParseSort(raw string) []SortField {
+	var fields []SortField
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		desc := strings.HasPrefix(part, "-")
+		part = strings.TrimPrefix(part, "-")
+		if part == "" {
+			continue
+		}
+		fields = append(fields, SortField{Field: part, Desc: desc})
+	}
+	return fields
+}
+
+// sortRule accepts a sort parameter whose fields are listed in the rule
+// parameter, separated by "|": validate:"sort=name|created_at"
+func sortRule(value reflect.Value, param string) error {
+	allowed := strings.Split(param, "|")
+	for _, f := range ParseSort(value.String()) {
+		if !IsStringInSlice(f.Field, allowed) {
+			return fmt.Errorf("cannot sort by %s; use %s", f.Field, strings.Join(allowed, ", "))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"sync/atomic"
+)
+
+// captureStacks enables stack capture in error constructors
+var captureStacks atomic.Bool
+
+// SetStackCapture turns stack capture in NewAppError, NewAppErrorWithCause
+// and NewInternalServerError on or off. It is off by default because
+// capturing costs an allocation per error.
+func SetStackCapture(enabled bool) {
+	captureStacks.Store(enabled)
+}
+
+// Stack is the call stack where an error was created
+type Stack []uintptr
+
+// callers returns the stack of the caller's caller when capture is enabled
+func callers() Stack {
+	if !captureStacks.Load() {
+		return nil
+	}
+	pcs := make([]uintptr, 32)
+	// Skip runtime.Callers, callers and the constructor
+	n := runtime.Callers(3, pcs)
+	return Stack(pcs[:n])
+}
+
+// Frames returns the stack frames, innermost first
+func (s Stack) Frames() []runtime.Frame {
+	if len(s) == 0 {
+		return nil
+	}
+	var frames []runtime.Frame
+	iter := runtime.CallersFrames(s)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			return frames
+		}
+	}
+}
+
+// writeTo writes one "function\n\tfile:line" pair per frame
+func (s Stack) writeTo(w io.Writer) {
+	for _, frame := range s.Frames() {
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", frame.Function, frame.File, frame.Line)
+	}
+}
+
+// formatError implements fmt.Formatter for errors with a stack: %+v adds
+// the stack and the cause chain, each cause formatted with %+v in turn
+func formatError(s fmt.State, verb rune, err error, stack Stack, cause error) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, err.Error())
+			stack.writeTo(s)
+			if cause != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", cause)
+			}
+			return
+		}
+		io.WriteString(s, err.Error())
+	case 's':
+		io.WriteString(s, err.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", err.Error())
+	}
+}
@@ -0,0 +1,315 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidateTag is the struct tag the validator reads, e.g.
+// `validate:"required,email,max=255"`
+const ValidateTag = "validate"
+
+// Rule checks a field value against the rule parameter, the text after
+// "=" in the tag. The message of the returned error is reported for the
+// field.
+type Rule func(value reflect.Value, param string) error
+
+// Validator validates structs by their validate tags. Fields are reported
+// by JSON path, e.g. items[2].email, and only the first failing rule of a
+// field is reported. Zero values skip every rule but required.
+type Validator struct {
+	mu     sync.RWMutex
+	rules  map[string]Rule
+	fields sync.Map // reflect.Type -> []fieldSpec
+}
+
+// fieldSpec is a parsed struct field
+type fieldSpec struct {
+	index int
+	name  string
+	// embedded fields share the path of their parent
+	embedded bool
+	skip     bool
+	rules    []ruleSpec
+}
+
+// ruleSpec is one rule of a validate tag
+type ruleSpec struct {
+	name  string
+	param string
+}
+
+// NewValidator creates a validator with the built-in rules
+func NewValidator() *Validator {
+	v := &Validator{rules: map[string]Rule{}}
+	for name, rule := range builtinRules {
+		v.rules[name] = rule
+	}
+	return v
+}
+
+// Register adds or replaces the rule used for name in tags
+func (v *Validator) Register(name string, rule Rule) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.rules[name] = rule
+}
+
+// Validate checks s, a struct, a pointer to one or a slice of them. It
+// returns nil, a *ValidationErrors listing every invalid field, or an
+// error when a tag names an unknown rule.
+func (v *Validator) Validate(s interface{}) error {
+	errs := NewValidationErrors()
+	if err := v.walk(reflect.ValueOf(s), "", errs); err != nil {
+		return err
+	}
+	if errs.HasErrors() {
+		return errs
+	}
+	return nil
+}
+
+// walk validates the fields of structs in value, descending into nested
+// structs, pointers and slices
+func (v *Validator) walk(value reflect.Value, path string, errs *ValidationErrors) error {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Struct:
+		for _, f := range v.specs(value.Type()) {
+			if f.skip {
+				continue
+			}
+			field := value.Field(f.index)
+			fieldPath := joinPath(path, f.name)
+			if f.embedded {
+				fieldPath = path
+			}
+			if err := v.check(field, fieldPath, f.rules, errs); err != nil {
+				return err
+			}
+			if err := v.walk(field, fieldPath, errs); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if !mayHoldStruct(value.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := v.walk(value.Index(i), fmt.Sprintf("%s[%d]", path, i), errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check runs the rules of one field, recording the first failure
+func (v *Validator) check(field reflect.Value, path string, rules []ruleSpec, errs *ValidationErrors) error {
+	if len(rules) == 0 {
+		return nil
+	}
+	zero := isZero(field)
+
+	for _, r := range rules {
+		v.mu.RLock()
+		rule, ok := v.rules[r.name]
+		v.mu.RUnlock()
+		if !ok {
+			return fmt.Errorf("utils: unknown validation rule %q on %s", r.name, path)
+		}
+		if zero && r.name != "required" {
+			continue
+		}
+		if err := rule(indirect(field), r.param); err != nil {
+			errs.Add(path, err.Error())
+			return nil
+		}
+	}
+	return nil
+}
+
+// specs returns the parsed fields of t, caching them per type
+func (v *Validator) specs(t reflect.Type) []fieldSpec {
+	if cached, ok := v.fields.Load(t); ok {
+		return cached.([]fieldSpec)
+	}
+
+	specs := make([]fieldSpec, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		spec := fieldSpec{index: i, name: jsonName(sf), embedded: sf.Anonymous && sf.Tag.Get("json") == ""}
+		tag := sf.Tag.Get(ValidateTag)
+		spec.skip = !sf.IsExported() || tag == "-"
+		spec.rules = parseRules(tag)
+		specs = append(specs, spec)
+	}
+
+	v.fields.Store(t, specs)
+	return specs
+}
+
+// parseRules splits a validate tag into its rules
+func parseRules(tag string) []ruleSpec {
+	if tag == "" || tag == "-" {
+		return nil
+	}
+	var rules []ruleSpec
+	for _, part := range strings.Split(tag, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(part, "=")
+		rules = append(rules, ruleSpec{name: name, param: param})
+	}
+	return rules
+}
+
+// jsonName is the JSON name of a field, or its Go name without one
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
+
+// joinPath appends name to a JSON path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// indirect follows pointers and interfaces down to the value
+func indirect(value reflect.Value) reflect.Value {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+// mayHoldStruct reports whether values of t can contain a struct to walk
+func mayHoldStruct(t reflect.Type) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Interface:
+		return true
+	case reflect.Slice, reflect.Array:
+		return mayHoldStruct(t.Elem())
+	}
+	return false
+}
+
+// isZero reports whether a field is missing: nil, the zero value, an
+// empty collection or a blank string
+func isZero(value reflect.Value) bool {
+	value = indirect(value)
+	switch value.Kind() {
+	case reflect.Invalid:
+		return true
+	case reflect.String:
+		return strings.TrimSpace(value.String()) == ""
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return value.Len() == 0
+	}
+	return value.IsZero()
+}
+
+// defaultValidator backs ValidateStruct and RegisterValidationRule
+var defaultValidator = NewValidator()
+
+// ValidateStruct validates s with the default validator
+func ValidateStruct(s interface{}) error {
+	return defaultValidator.Validate(s)
+}
+
+// RegisterValidationRule adds a rule to the default validator
+func RegisterValidationRule(name string, rule Rule) {
+	defaultValidator.Register(name, rule)
+}
+
+// builtinRules are the rules every validator starts with
+var builtinRules = map[string]Rule{
+	"required": func(value reflect.Value, _ string) error {
+		if isZero(value) {
+			return errors.New("is required")
+		}
+		return nil
+	},
+	"min":      sizeRule("at least", func(n, limit float64) bool { return n >= limit }),
+	"max":      sizeRule("at most", func(n, limit float64) bool { return n <= limit }),
+	"oneof":    oneOfRule,
+	"sort":     sortRule,
+	"email":    stringRule(IsValidEmail, "must be a valid email address"),
+	"uuid":     stringRule(IsValidUUID, "must be a valid UUID"),
+	"url":      stringRule(IsValidURL, "must be a valid URL"),
+	"ip":       stringRule(IsValidIP, "must be a valid IPv4 address"),
+	"phone":    stringRule(IsValidPhoneNumber, "must be a valid phone number"),
+	"username": stringRule(IsValidUsername, "must be 3-20 letters, digits, underscores or hyphens"),
+	"password": stringRule(IsValidPassword, "must be at least 8 characters with an uppercase letter, a lowercase letter and a digit"),
+}
+
+// stringRule adapts an IsValid* function to a rule on string fields
+func stringRule(valid func(string) bool, message string) Rule {
+	return func(value reflect.Value, _ string) error {
+		if value.Kind() != reflect.String || !valid(value.String()) {
+			return errors.New(message)
+		}
+		return nil
+	}
+}
+
+// sizeRule compares the length of strings (in characters) and
+// collections (in items), or the value of numbers, with the parameter
+func sizeRule(bound string, ok func(n, limit float64) bool) Rule {
+	return func(value reflect.Value, param string) error {
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return fmt.Errorf("has an invalid limit %q", param)
+		}
+
+		var n float64
+		unit := ""
+		switch value.Kind() {
+		case reflect.String:
+			n, unit = float64(utf8.RuneCountInString(value.String())), " characters"
+		case reflect.Slice, reflect.Map, reflect.Array:
+			n, unit = float64(value.Len()), " items"
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			n = float64(value.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			n = float64(value.Uint())
+		case reflect.Float32, reflect.Float64:
+			n = value.Float()
+		default:
+			return fmt.Errorf("cannot be checked with %s %s", bound, param)
+		}
+
+		if !ok(n, limit) {
+			return fmt.Errorf("must be %s %s%s", bound, param, unit)
+		}
+		return nil
+	}
+}
+
+// oneOfRule accepts a value listed in the parameter, separated by "|"
+func oneOfRule(value reflect.Value, param string) error {
+	options := strings.Split(param, "|")
+	if IsStringInSlice(fmt.Sprint(value.Interface()), options) {
+		return nil
+	}
+	return fmt.Errorf("must be one of %s", strings.Join(options, ", "))
+}
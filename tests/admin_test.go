@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	handlers "github.com/miladev95/golang-project-structure/internal/handlers/http"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// stubGraph is a DependencyGraph with fixed output
+type stubGraph struct{}
+
+func (stubGraph) WriteDOT(w io.Writer) error {
+	_, err := io.WriteString(w, "digraph {}")
+	return err
+}
+
+func (stubGraph) WriteJSON(w io.Writer) error {
+	_, err := io.WriteString(w, "{}")
+	return err
+}
+
+func TestDebugRoutesWithAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	debugHandler := handlers.NewDebugHandler(stubGraph{})
+	auth := middleware.AdminTokenMiddleware("secret")
+	routes.RegisterAll(router,
+		routes.NewDebugRouter(debugHandler, auth),
+		routes.NewDIRouter(debugHandler, auth),
+	)
+
+	tests := []struct {
+		name   string
+		path   string
+		token  string
+		status int
+	}{
+		{"missing token", "/debug/vars", "", http.StatusUnauthorized},
+		{"wrong token", "/debug/vars", "wrong", http.StatusUnauthorized},
+		{"metrics", "/debug/vars", "secret", http.StatusOK},
+		{"pprof index", "/debug/pprof/", "secret", http.StatusOK},
+		{"di graph", "/admin/di/graph?format=dot", "secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminTokenMiddlewareRejectsEmptyToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.GET("/admin", middleware.AdminTokenMiddleware(""), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, header := range []string{"", "Bearer ", "Bearer x"} {
+		req, _ := http.NewRequest("GET", "/admin", nil)
+		req.Header.Set("Authorization", header)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("Expected 401 for %q, got %d", header, w.Code)
+		}
+	}
+}
+
+func TestAdminPortOverlapValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		host      string
+		adminHost string
+		adminPort string
+		overlap   bool
+	}{
+		{"defaults on the same port", "0.0.0.0", "127.0.0.1", "8080", true},
+		{"empty public host", "", "127.0.0.1", "8080", true},
+		{"IPv6 wildcard admin host", "10.0.0.1", "::", "8080", true},
+		{"same host", "10.0.0.1", "10.0.0.1", "8080", true},
+		{"different hosts", "10.0.0.1", "127.0.0.1", "8080", false},
+		{"different ports", "0.0.0.0", "127.0.0.1", "9090", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.LoadConfig()
+			cfg.Server.Host = tt.host
+			cfg.Server.Port = "8080"
+			cfg.Server.AdminHost = tt.adminHost
+			cfg.Server.AdminPort = tt.adminPort
+
+			overlap := false
+			var verrs *utils.ValidationErrors
+			if err := cfg.Validate(); errors.As(err, &verrs) {
+				for _, e := range verrs.Errors {
+					overlap = overlap || e.Field == "ADMIN_PORT"
+				}
+			}
+			if overlap != tt.overlap {
+				t.Errorf("Expected ADMIN_PORT overlap %v, got %v", tt.overlap, overlap)
+			}
+		})
+	}
+}
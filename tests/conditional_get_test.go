@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+var conditionalUpdatedAt = time.Date(2024, 5, 6, 7, 8, 9, 500, time.UTC)
+
+// newConditionalRouter serves the user routes with one user, at version 3
+func newConditionalRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	user := models.User{ID: 1, Name: "Jane", Email: "jane@example.com", Version: 3, UpdatedAt: conditionalUpdatedAt}
+
+	return newUserTestRouter(t, withUserService(&MockUserService{
+		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
+			if id != user.ID {
+				return nil, utils.NewNotFoundError("user", id)
+			}
+			return &user, nil
+		},
+		ListUsersFunc: func(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error) {
+			return []models.User{user}, 1, nil
+		},
+	}), withUserCachePolicy(routes.UserCachePolicy{
+		User:  "private, no-cache",
+		Users: "public, max-age=30",
+	}))
+}
+
+func conditionalGet(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConditionalGetListETag(t *testing.T) {
+	router := newConditionalRouter(t)
+
+	w := conditionalGet(router, "/api/v1/users", nil)
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Body.Len() == 0 {
+		t.Fatalf("expected 200 with body and ETag, got %d %q", w.Code, etag)
+	}
+	if cc := w.Header().Get("Cache-Control"); cc != "public, max-age=30" {
+		t.Errorf("expected list Cache-Control, got %q", cc)
+	}
+
+	for _, inm := range []string{etag, "W/" + etag, `"other", ` + etag, "*"} {
+		w = conditionalGet(router, "/api/v1/users", map[string]string{"If-None-Match": inm})
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: expected empty 304, got %d %q", inm, w.Code, w.Body.String())
+		}
+		if w.Header().Get("ETag") != etag || w.Header().Get("Cache-Control") == "" {
+			t.Errorf("If-None-Match %s: expected validators on 304, got %v", inm, w.Header())
+		}
+	}
+
+	w = conditionalGet(router, "/api/v1/users", map[string]string{"If-None-Match": `"stale"`})
+	if w.Code != http.StatusOK || w.Body.Len() == 0 {
+		t.Errorf("expected 200 for a stale ETag, got %d", w.Code)
+	}
+}
+
+func TestConditionalGetUserValidators(t *testing.T) {
+	router := newConditionalRouter(t)
+	lastModified := conditionalUpdatedAt.Format(http.TimeFormat)
+
+	w := conditionalGet(router, "/api/v1/users/1", nil)
+	if w.Header().Get("ETag") != `"3"` {
+		t.Errorf(`expected the version ETag "3", got %q`, w.Header().Get("ETag"))
+	}
+	if w.Header().Get("Last-Modified") != lastModified {
+		t.Errorf("expected Last-Modified %s, got %q", lastModified, w.Header().Get("Last-Modified"))
+	}
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		status  int
+	}{
+		{"matching ETag", map[string]string{"If-None-Match": `"3"`}, http.StatusNotModified},
+		{"not modified since", map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": conditionalUpdatedAt.Add(-time.Minute).Format(http.TimeFormat)}, http.StatusOK},
+		{"If-None-Match wins", map[string]string{"If-None-Match": `"2"`, "If-Modified-Since": lastModified}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := conditionalGet(router, "/api/v1/users/1", tt.headers)
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
+
+func TestConditionalGetPassesErrorsThrough(t *testing.T) {
+	router := newConditionalRouter(t)
+
+	w := conditionalGet(router, "/api/v1/users/2", map[string]string{"If-None-Match": "*"})
+	if w.Code != http.StatusNotFound || w.Body.Len() == 0 {
+		t.Fatalf("expected 404 with body, got %d %q", w.Code, w.Body.String())
+	}
+	if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+		t.Errorf("expected no caching headers on errors, got %v", w.Header())
+	}
+}
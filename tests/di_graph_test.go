@@ -0,0 +1,150 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+)
+
+func TestContainerGraph(t *testing.T) {
+	container := newTestContainer(t)
+	graph := container.Graph()
+
+	t.Run("annotates providers with modules", func(t *testing.T) {
+		if p := findProvider(graph, "*gorm.DB"); p == nil || p.Module != "core" {
+			t.Errorf("Expected core to provide *gorm.DB, got %+v", p)
+		}
+		if p := findProvider(graph, "*tests.greeter"); p == nil || p.Module != "greeter" {
+			t.Errorf("Expected greeter to provide *tests.greeter, got %+v", p)
+		}
+
+		spanish := false
+		for _, p := range graph.Providers {
+			for _, out := range p.Outputs {
+				spanish = spanish || (out.Type == "*tests.greeter" && out.Name == "spanish" && p.Module == "greeter")
+			}
+		}
+		if !spanish {
+			t.Error("Expected the named spanish greeter in the greeter module")
+		}
+	})
+
+	t.Run("records dependency edges", func(t *testing.T) {
+		graph := newUserGraphContainer(t).Graph()
+
+		service := findProvider(graph, "services.UserService")
+		if service == nil || service.Module != "user" {
+			t.Fatalf("Expected the user module to provide services.UserService, got %+v", service)
+		}
+		if !hasInput(service, "repositories.UserRepository") {
+			t.Errorf("Expected UserService to depend on UserRepository, got %+v", service.Inputs)
+		}
+
+		repo := findProvider(graph, "repositories.UserRepository")
+		if repo == nil || !hasInput(repo, "*gorm.DB") {
+			t.Errorf("Expected UserRepository to depend on *gorm.DB, got %+v", repo)
+		}
+	})
+
+	t.Run("reports missing inputs", func(t *testing.T) {
+		if len(graph.Missing) != 1 || graph.Missing[0].Type != "*tests.farewell" {
+			t.Errorf("Expected *tests.farewell to be missing, got %+v", graph.Missing)
+		}
+	})
+
+	t.Run("writes json", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.WriteJSON(&buf); err != nil {
+			t.Fatalf("WriteJSON failed: %v", err)
+		}
+
+		var decoded map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if _, ok := decoded["providers"]; !ok {
+			t.Error("Expected providers in JSON output")
+		}
+	})
+
+	t.Run("writes dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := graph.WriteDOT(&buf); err != nil {
+			t.Fatalf("WriteDOT failed: %v", err)
+		}
+
+		dot := buf.String()
+		if !strings.HasPrefix(dot, "digraph {") {
+			t.Errorf("Expected DOT digraph, got %q", dot)
+		}
+		if !strings.Contains(dot, `"cluster_greeter"`) {
+			t.Error("Expected a cluster for the greeter module")
+		}
+		if !strings.Contains(dot, "(missing)") {
+			t.Error("Expected missing input to be marked")
+		}
+	})
+}
+
+// newUserGraphContainer sets up the user module without opening a database
+func newUserGraphContainer(t *testing.T) *di.Container {
+	t.Helper()
+	container := di.NewContainer().RegisterModule(modules.NewUserModule())
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	return container
+}
+
+// findProvider returns the unnamed, ungrouped provider of typ
+func findProvider(graph *di.Graph, typ string) *di.GraphProvider {
+	for i, p := range graph.Providers {
+		for _, out := range p.Outputs {
+			if out.Type == typ && out.Name == "" && out.Group == "" {
+				return &graph.Providers[i]
+			}
+		}
+	}
+	return nil
+}
+
+// hasInput reports whether p takes a value of typ
+func hasInput(p *di.GraphProvider, typ string) bool {
+	for _, in := range p.Inputs {
+		if in.Type == typ {
+			return true
+		}
+	}
+	return false
+}
+
+func TestContainerDoctor(t *testing.T) {
+	container := newTestContainer(t)
+	report := container.Doctor()
+
+	if report.OK() {
+		t.Fatal("Expected doctor to report failures")
+	}
+	if report.Err() == nil {
+		t.Error("Expected joined error")
+	}
+
+	// string fails on the missing *farewell; *gorm.DB may fail as well when
+	// no database is reachable
+	for _, f := range report.Failures {
+		if f.Module == "" {
+			t.Errorf("Failure should name its module: %v", f)
+		}
+	}
+
+	var buf bytes.Buffer
+	report.Write(&buf)
+	if !strings.Contains(buf.String(), "FAIL") {
+		t.Errorf("Expected FAIL lines in report, got %q", buf.String())
+	}
+}
@@ -0,0 +1,344 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+	"github.com/miladev95/golang-project-structure/internal/events"
+	handlers "github.com/miladev95/golang-project-structure/internal/handlers/http"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/internal/services"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// FakeUserRepository is an in-memory repositories.UserRepository
+type FakeUserRepository struct {
+	mu     sync.Mutex
+	users  map[int64]models.User
+	nextID int64
+}
+
+func NewFakeUserRepository() *FakeUserRepository {
+	return &FakeUserRepository{users: make(map[int64]models.User), nextID: 1}
+}
+
+func (r *FakeUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, utils.NewNotFoundError("user", id)
+	}
+	return &user, nil
+}
+
+func (r *FakeUserRepository) List(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := query.Filter
+	users := []models.User{}
+	for _, u := range r.users {
+		switch {
+		case f.NameContains != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(f.NameContains)):
+		case f.Email != "" && u.Email != f.Email:
+		case f.CreatedFrom != nil && u.CreatedAt.Before(*f.CreatedFrom):
+		case f.CreatedTo != nil && !u.CreatedAt.Before(*f.CreatedTo):
+		default:
+			users = append(users, u)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		for _, s := range query.Sort {
+			a, b := fakeUserSortKey(users[i], s.Field), fakeUserSortKey(users[j], s.Field)
+			if a != b {
+				return (a < b) != s.Desc
+			}
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	total := int64(len(users))
+	start := min(query.Pagination.GetOffset(), len(users))
+	end := min(start+query.Pagination.GetLimit(), len(users))
+	return users[start:end], total, nil
+}
+
+// fakeUserSortKey is the value users are sorted by for field
+func fakeUserSortKey(u models.User, field string) string {
+	switch field {
+	case "name":
+		return u.Name
+	case "email":
+		return u.Email
+	case "created_at":
+		return u.CreatedAt.Format(time.RFC3339Nano)
+	case "updated_at":
+		return u.UpdatedAt.Format(time.RFC3339Nano)
+	}
+	return fmt.Sprintf("%020d", u.ID)
+}
+
+func (r *FakeUserRepository) Create(ctx context.Context, user *models.User) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.ID = r.nextID
+	user.Version = 1
+	r.nextID++
+	r.users[user.ID] = *user
+	return user, nil
+}
+
+func (r *FakeUserRepository) Update(ctx context.Context, user *models.User) error {
+	return r.UpdateFields(ctx, user, []string{"name", "email"})
+}
+
+func (r *FakeUserRepository) UpdateFields(ctx context.Context, user *models.User, fields []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, err := r.current(user.ID, user.Version)
+	if err != nil {
+		return err
+	}
+	for _, field := range fields {
+		switch field {
+		case "name":
+			stored.Name = user.Name
+		case "email":
+			stored.Email = user.Email
+		}
+	}
+	stored.Version++
+	r.users[user.ID] = stored
+	*user = stored
+	return nil
+}
+
+func (r *FakeUserRepository) Delete(ctx context.Context, id int64, version int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, err := r.current(id, version); err != nil {
+		return err
+	}
+	delete(r.users, id)
+	return nil
+}
+
+// current returns the stored user if it is still at version
+func (r *FakeUserRepository) current(id, version int64) (models.User, error) {
+	stored, ok := r.users[id]
+	if !ok {
+		return models.User{}, utils.NewNotFoundError("user", id)
+	}
+	if stored.Version != version {
+		return models.User{}, utils.NewFieldConflictError("version", "the user was modified by another request")
+	}
+	return stored, nil
+}
+
+// newLazyDB returns a *gorm.DB that never opens a connection
+func newLazyDB(t testing.TB) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=invalid"}), &gorm.Config{
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create lazy DB: %v", err)
+	}
+	return db
+}
+
+// userTestRouter holds what newUserTestRouter replaces in the container
+type userTestRouter struct {
+	tx        transaction.Manager
+	cache     routes.UserCachePolicy
+	overrides []func(c *di.Container)
+}
+
+// userTestOption configures newUserTestRouter
+type userTestOption func(r *userTestRouter)
+
+// withUserRepository replaces the user repository, so requests run through
+// the real user service
+func withUserRepository(repo repositories.UserRepository) userTestOption {
+	return func(r *userTestRouter) {
+		r.overrides = append(r.overrides, func(c *di.Container) { di.Replace[repositories.UserRepository](c, repo) })
+	}
+}
+
+// withUserService replaces the whole user service
+func withUserService(service services.UserService) userTestOption {
+	return func(r *userTestRouter) {
+		r.overrides = append(r.overrides, func(c *di.Container) { di.Replace[services.UserService](c, service) })
+	}
+}
+
+// withTxManager replaces the transaction manager instead of a fresh FakeTxManager
+func withTxManager(tx transaction.Manager) userTestOption {
+	return func(r *userTestRouter) { r.tx = tx }
+}
+
+// withEventBus replaces the event bus the user service publishes to
+func withEventBus(bus *events.Bus) userTestOption {
+	return func(r *userTestRouter) {
+		r.overrides = append(r.overrides, func(c *di.Container) { di.Replace[*events.Bus](c, bus) })
+	}
+}
+
+// withUserCachePolicy sets the Cache-Control of the user read routes
+func withUserCachePolicy(cache routes.UserCachePolicy) userTestOption {
+	return func(r *userTestRouter) { r.cache = cache }
+}
+
+// newUserTestRouter wires the real user module through the container and
+// serves its routes. The database and transaction manager are always
+// replaced so no connection is opened.
+func newUserTestRouter(tb testing.TB, opts ...userTestOption) *gin.Engine {
+	tb.Helper()
+	gin.SetMode(gin.TestMode)
+
+	r := &userTestRouter{tx: &FakeTxManager{}}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	container := di.NewContainer().RegisterModule(modules.NewUserModule())
+	di.Replace[*gorm.DB](container, newLazyDB(tb))
+	di.Replace[transaction.Manager](container, r.tx)
+	for _, override := range r.overrides {
+		override(container)
+	}
+
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		tb.Fatalf("Setup failed: %v", err)
+	}
+
+	handler, err := di.Resolve[*handlers.UserHandler](container)
+	if err != nil {
+		tb.Fatalf("Failed to resolve user handler: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(middleware.ErrorMiddleware())
+	routes.RegisterAll(router, routes.NewUserRouter(handler, r.cache))
+	return router
+}
+
+// sendUserRequest sends an authorized request to the user routes
+func sendUserRequest(router *gin.Engine, method, path, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Authorization", "Bearer test-token-123")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// TestUserModuleWiring exercises the user routes through the DI container
+func TestUserModuleWiring(t *testing.T) {
+	repo := NewFakeUserRepository()
+	tx := &FakeTxManager{}
+	router := newUserTestRouter(t, withUserRepository(repo), withTxManager(tx))
+
+	w := sendUserRequest(router, http.MethodPost, "/api/v1/users", "application/json", `{"name":"Jane Doe","email":"jane@example.com"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+	if _, err := repo.GetByID(context.Background(), 1); err != nil {
+		t.Errorf("Expected user to be stored in the fake repository: %v", err)
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v1/users/1", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var response map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	data := response["data"].(map[string]interface{})
+	if data["email"] != "jane@example.com" {
+		t.Errorf("Expected email jane@example.com, got %v", data["email"])
+	}
+
+	w = sendUserRequest(router, http.MethodPut, "/api/v1/users/1", "application/json", `{"name":"Janet","email":"jane@example.com"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected PUT status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	w = sendUserRequest(router, http.MethodPatch, "/api/v1/users/1", "application/merge-patch+json", `{"email":"janet@example.com"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected PATCH status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	stored, _ := repo.GetByID(context.Background(), 1)
+	if stored.Name != "Janet" || stored.Email != "janet@example.com" || stored.Version != 3 {
+		t.Errorf("Expected both writes in the fake repository, got %+v", stored)
+	}
+
+	w = sendUserRequest(router, http.MethodDelete, "/api/v1/users/1", "", "")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected DELETE status %d, got %d: %s", http.StatusNoContent, w.Code, w.Body.String())
+	}
+	if _, err := repo.GetByID(context.Background(), 1); err == nil {
+		t.Error("Expected user to be deleted from the fake repository")
+	}
+
+	if tx.Calls != 3 {
+		t.Errorf("Expected PUT, PATCH and DELETE to use the fake transaction manager, got %d calls", tx.Calls)
+	}
+}
+
+// TestOverrideWrapsOriginal tests an override that decorates the original value
+func TestOverrideWrapsOriginal(t *testing.T) {
+	container := di.NewContainer().RegisterModule(&greeterModule{})
+	container.Override(func(g *greeter) *greeter {
+		return &greeter{greeting: g.greeting + ", world"}
+	})
+
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	g, err := di.Resolve[*greeter](container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if g.greeting != "hello, world" {
+		t.Errorf("greeting: got %q, want %q", g.greeting, "hello, world")
+	}
+}
+
+// TestOverrideTwiceFails tests that a type can only be overridden once
+func TestOverrideTwiceFails(t *testing.T) {
+	container := di.NewContainer().RegisterModule(&greeterModule{})
+	di.Replace(container, &greeter{greeting: "hi"})
+	di.Replace(container, &greeter{greeting: "hey"})
+
+	if err := container.Setup(config.LoadConfig()); err == nil {
+		t.Error("Expected Setup to fail for duplicate override")
+	}
+}
@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+	"github.com/miladev95/golang-project-structure/internal/di/scope"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+)
+
+// requestGreeter is created once per request
+type requestGreeter struct {
+	greeting string
+	path     string
+}
+
+type requestModule struct {
+	created  *int32
+	disposed *int32
+}
+
+func (m *requestModule) Name() string { return "request" }
+
+func (m *requestModule) Register(container *modules.Binder) error {
+	return container.ProvideScoped(func(g *greeter, req *http.Request, d di.Disposer) *requestGreeter {
+		atomic.AddInt32(m.created, 1)
+		d.OnDispose(func() { atomic.AddInt32(m.disposed, 1) })
+		return &requestGreeter{greeting: g.greeting, path: req.URL.Path}
+	})
+}
+
+func TestRequestScopeMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var created, disposed int32
+	container := di.NewContainer().
+		RegisterModule(&greeterModule{}).
+		RegisterModule(&requestModule{created: &created, disposed: &disposed})
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(middleware.RequestScopeMiddleware(container))
+	router.GET("/greet/:name", func(c *gin.Context) {
+		first, err := scope.Resolve[*requestGreeter](c.Request.Context())
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		second, _ := scope.Resolve[*requestGreeter](c.Request.Context())
+		if first != second {
+			c.String(http.StatusInternalServerError, "expected one instance per request")
+			return
+		}
+		c.String(http.StatusOK, first.greeting+" "+first.path)
+	})
+
+	for _, path := range []string{"/greet/a", "/greet/b"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		if want := "hello " + path; w.Body.String() != want {
+			t.Errorf("Body: got %q, want %q", w.Body.String(), want)
+		}
+	}
+
+	if created != 2 {
+		t.Errorf("Expected 2 request-scoped instances, got %d", created)
+	}
+	if disposed != 2 {
+		t.Errorf("Expected 2 disposals, got %d", disposed)
+	}
+}
+
+func TestRequestScopeShadowsSingletons(t *testing.T) {
+	container := newTestContainer(t)
+
+	requestScope, err := container.NewRequestScope(func() *greeter {
+		return &greeter{greeting: "request"}
+	})
+	if err != nil {
+		t.Fatalf("NewRequestScope failed: %v", err)
+	}
+	defer requestScope.Dispose()
+
+	g, err := di.Resolve[*greeter](requestScope.Container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if g.greeting != "request" {
+		t.Errorf("greeting: got %q, want request", g.greeting)
+	}
+
+	named, err := di.ResolveNamed[*greeter](requestScope.Container, "spanish")
+	if err != nil {
+		t.Fatalf("ResolveNamed failed: %v", err)
+	}
+	if named.greeting != "hola" {
+		t.Errorf("named greeting: got %q, want hola", named.greeting)
+	}
+}
+
+func TestRequestScopeSharesSingletons(t *testing.T) {
+	container := newTestContainer(t)
+
+	var resolved []*greeter
+	for i := 0; i < 2; i++ {
+		requestScope, err := container.NewRequestScope()
+		if err != nil {
+			t.Fatalf("NewRequestScope failed: %v", err)
+		}
+		g, err := di.Resolve[*greeter](requestScope.Container)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		resolved = append(resolved, g)
+		requestScope.Dispose()
+	}
+
+	root, _ := di.Resolve[*greeter](container)
+	if resolved[0] != root || resolved[1] != root {
+		t.Error("Expected every request scope to get the root singleton")
+	}
+}
+
+func TestRequestScopeAppliesOverrides(t *testing.T) {
+	var created, disposed int32
+	container := di.NewContainer().
+		RegisterModule(&greeterModule{}).
+		RegisterModule(&requestModule{created: &created, disposed: &disposed})
+	di.Replace(container, &greeter{greeting: "hi"})
+	container.Override(func(g *requestGreeter) *requestGreeter {
+		return &requestGreeter{greeting: g.greeting + "!", path: g.path}
+	})
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/greet", nil)
+	requestScope, err := container.NewRequestScope(func() *http.Request { return req })
+	if err != nil {
+		t.Fatalf("NewRequestScope failed: %v", err)
+	}
+	defer requestScope.Dispose()
+
+	g, err := di.Resolve[*requestGreeter](requestScope.Container)
+	if err != nil {
+		t.Fatalf("Resolve failed: %v", err)
+	}
+	if g.greeting != "hi!" {
+		t.Errorf("Expected both overrides applied, got %q", g.greeting)
+	}
+}
+
+// principalKey is a context key set by a middleware after the scope
+type principalKey struct{}
+
+func TestRequestScopeContextIncludesLaterMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(middleware.RequestScopeMiddleware(newTestContainer(t)))
+	router.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), principalKey{}, "billing-service"))
+	})
+	router.GET("/whoami", func(c *gin.Context) {
+		ctx, err := scope.Resolve[context.Context](c.Request.Context())
+		if err != nil {
+			c.String(http.StatusInternalServerError, err.Error())
+			return
+		}
+		principal, _ := ctx.Value(principalKey{}).(string)
+		c.String(http.StatusOK, principal)
+	})
+
+	req, _ := http.NewRequest("GET", "/whoami", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || w.Body.String() != "billing-service" {
+		t.Errorf("Expected the principal in the scope's context, got %d %q", w.Code, w.Body.String())
+	}
+}
+
+func TestScopeResolveWithoutScope(t *testing.T) {
+	if _, err := scope.Resolve[*greeter](context.Background()); err != scope.ErrNoScope {
+		t.Errorf("Expected ErrNoScope, got %v", err)
+	}
+}
+
+// BenchmarkRequestScope creates a scope per request on the full user module
+// graph, resolving one request-scoped value that depends on a singleton
+func BenchmarkRequestScope(b *testing.B) {
+	var created, disposed int32
+	container := di.NewContainer().
+		RegisterModule(modules.NewUserModule()).
+		RegisterModule(&greeterModule{}).
+		RegisterModule(&requestModule{created: &created, disposed: &disposed})
+	di.Replace[*gorm.DB](container, newLazyDB(b))
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		b.Fatalf("Setup failed: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/greet", nil)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		requestScope, err := container.NewRequestScope(
+			func() context.Context { return req.Context() },
+			func() *http.Request { return req },
+		)
+		if err != nil {
+			b.Fatalf("NewRequestScope failed: %v", err)
+		}
+		if _, err := di.Resolve[*requestGreeter](requestScope.Container); err != nil {
+			b.Fatalf("Resolve failed: %v", err)
+		}
+		requestScope.Dispose()
+	}
+}
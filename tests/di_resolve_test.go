@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/dig"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/di/modules"
+)
+
+type greeter struct{ greeting string }
+
+type farewell struct{ message string }
+
+// greeterModule registers test values through the module system
+type greeterModule struct{}
+
+func (m *greeterModule) Name() string { return "greeter" }
+
+func (m *greeterModule) Register(container *modules.Binder) error {
+	if err := container.Provide(func() *greeter {
+		return &greeter{greeting: "hello"}
+	}); err != nil {
+		return err
+	}
+
+	if err := container.Provide(func() *greeter {
+		return &greeter{greeting: "hola"}
+	}, dig.Name("spanish")); err != nil {
+		return err
+	}
+
+	for _, msg := range []string{"bye", "adios"} {
+		msg := msg
+		if err := container.Provide(func() *farewell {
+			return &farewell{message: msg}
+		}, dig.Group("farewells")); err != nil {
+			return err
+		}
+	}
+
+	// Depends on a type nobody provides
+	return container.Provide(func(f *farewell) string { return f.message })
+}
+
+func newTestContainer(t *testing.T) *di.Container {
+	t.Helper()
+
+	container := di.NewContainer().RegisterModule(&greeterModule{})
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	return container
+}
+
+func TestResolve(t *testing.T) {
+	container := newTestContainer(t)
+
+	t.Run("resolves registered type", func(t *testing.T) {
+		g, err := di.Resolve[*greeter](container)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if g.greeting != "hello" {
+			t.Errorf("greeting: got %s, want hello", g.greeting)
+		}
+	})
+
+	t.Run("resolves core types", func(t *testing.T) {
+		cfg, err := di.Resolve[*config.Config](container)
+		if err != nil {
+			t.Fatalf("Resolve failed: %v", err)
+		}
+		if cfg == nil {
+			t.Error("Expected config to be resolved")
+		}
+	})
+
+	t.Run("missing type names registered modules", func(t *testing.T) {
+		_, err := di.Resolve[*farewell](container)
+		if err == nil {
+			t.Fatal("Expected error for ungrouped type")
+		}
+
+		var resolveErr *di.ResolveError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected *di.ResolveError, got %T", err)
+		}
+		if resolveErr.Module != "" {
+			t.Errorf("Module: got %q, want empty", resolveErr.Module)
+		}
+		if !strings.Contains(err.Error(), "*tests.farewell") || !strings.Contains(err.Error(), "greeter") {
+			t.Errorf("Error should name the type and registered modules, got %q", err.Error())
+		}
+	})
+
+	t.Run("failing dependency names providing module", func(t *testing.T) {
+		_, err := di.Resolve[string](container)
+
+		var resolveErr *di.ResolveError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected *di.ResolveError, got %v", err)
+		}
+		if resolveErr.Module != "greeter" {
+			t.Errorf("Module: got %q, want greeter", resolveErr.Module)
+		}
+	})
+}
+
+// inventory fails to build; shop depends on it from another module
+type inventory struct{}
+
+type shop struct{ inventory *inventory }
+
+type inventoryModule struct{}
+
+func (m *inventoryModule) Name() string { return "inventory" }
+
+func (m *inventoryModule) Register(container *modules.Binder) error {
+	return container.Provide(func() (*inventory, error) { return nil, errors.New("warehouse offline") })
+}
+
+type shopModule struct{}
+
+func (m *shopModule) Name() string { return "shop" }
+
+func (m *shopModule) Register(container *modules.Binder) error {
+	if err := container.Provide(func(i *inventory) *shop { return &shop{inventory: i} }); err != nil {
+		return err
+	}
+	return container.Provide(func(s *shop, g *greeter) *farewell { return &farewell{message: "closed"} }, dig.Name("shop"))
+}
+
+func TestResolveErrorNamesTransitiveDependency(t *testing.T) {
+	container := di.NewContainer().RegisterModule(&inventoryModule{}).RegisterModule(&shopModule{})
+	if err := container.Setup(config.LoadConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+
+	t.Run("failed constructor", func(t *testing.T) {
+		_, err := di.Resolve[*shop](container)
+
+		var resolveErr *di.ResolveError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected *di.ResolveError, got %v", err)
+		}
+		if resolveErr.Module != "shop" || resolveErr.Dependency != "*tests.inventory" || resolveErr.DependencyModule != "inventory" {
+			t.Errorf("Expected *tests.inventory from module inventory, got %+v", resolveErr)
+		}
+		if !strings.Contains(err.Error(), "warehouse offline") {
+			t.Errorf("Error should keep the cause, got %q", err.Error())
+		}
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		_, err := di.ResolveNamed[*farewell](container, "shop")
+
+		var resolveErr *di.ResolveError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected *di.ResolveError, got %v", err)
+		}
+		if resolveErr.Module != "shop" || resolveErr.DependencyModule != "" || !strings.Contains(resolveErr.Dependency, "greeter") {
+			t.Errorf("Expected the missing *tests.greeter with no module, got %+v", resolveErr)
+		}
+		if !strings.Contains(err.Error(), "no registered module provides dependency") {
+			t.Errorf("Error should name the missing dependency, got %q", err.Error())
+		}
+	})
+
+	t.Run("own constructor", func(t *testing.T) {
+		_, err := di.Resolve[*inventory](container)
+
+		var resolveErr *di.ResolveError
+		if !errors.As(err, &resolveErr) {
+			t.Fatalf("Expected *di.ResolveError, got %v", err)
+		}
+		if resolveErr.Module != "inventory" || resolveErr.Dependency != "" {
+			t.Errorf("Expected no transitive dependency, got %+v", resolveErr)
+		}
+	})
+}
+
+func TestMustResolvePanics(t *testing.T) {
+	container := newTestContainer(t)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustResolve to panic")
+		}
+	}()
+	di.MustResolve[*farewell](container)
+}
+
+func TestResolveNamed(t *testing.T) {
+	container := newTestContainer(t)
+
+	g, err := di.ResolveNamed[*greeter](container, "spanish")
+	if err != nil {
+		t.Fatalf("ResolveNamed failed: %v", err)
+	}
+	if g.greeting != "hola" {
+		t.Errorf("greeting: got %s, want hola", g.greeting)
+	}
+
+	if _, err := di.ResolveNamed[*greeter](container, "french"); err == nil {
+		t.Error("Expected error for unknown name")
+	}
+}
+
+func TestResolveGroup(t *testing.T) {
+	container := newTestContainer(t)
+
+	farewells, err := di.ResolveGroup[*farewell](container, "farewells")
+	if err != nil {
+		t.Fatalf("ResolveGroup failed: %v", err)
+	}
+	if len(farewells) != 2 {
+		t.Errorf("Expected 2 farewells, got %d", len(farewells))
+	}
+}
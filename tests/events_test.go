@@ -0,0 +1,201 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/internal/events"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/services"
+)
+
+// orderPlaced is a test event
+type orderPlaced struct {
+	OrderID int
+	Seq     int
+}
+
+func (e orderPlaced) EventName() string   { return "order.placed" }
+func (e orderPlaced) AggregateID() string { return strconv.Itoa(e.OrderID) }
+
+func TestBusSyncSubscribersIsolatePanics(t *testing.T) {
+	bus := events.NewBus(events.Options{})
+	defer bus.Close()
+
+	var calls []string
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		calls = append(calls, "first")
+		panic("boom")
+	}, events.Named("first"))
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		calls = append(calls, "second")
+		return errors.New("out of stock")
+	})
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		calls = append(calls, "third")
+		return nil
+	})
+
+	err := bus.Publish(context.Background(), orderPlaced{OrderID: 1})
+	if err == nil {
+		t.Fatal("Expected subscriber errors to be returned")
+	}
+	if len(calls) != 3 {
+		t.Errorf("Expected every subscriber to run, got %v", calls)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "first panicked on order.placed: boom") || !strings.Contains(msg, "out of stock") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestBusAsyncSubscribersKeepAggregateOrder(t *testing.T) {
+	bus := events.NewBus(events.Options{Shards: 4, BufferSize: 1})
+
+	var mu sync.Mutex
+	seen := make(map[int][]int)
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[e.OrderID] = append(seen[e.OrderID], e.Seq)
+		return nil
+	}, events.Async())
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		panic("async subscribers are isolated too")
+	}, events.Async())
+
+	for seq := 0; seq < 50; seq++ {
+		for order := 1; order <= 5; order++ {
+			if err := bus.Publish(context.Background(), orderPlaced{OrderID: order, Seq: seq}); err != nil {
+				t.Fatalf("Publish failed: %v", err)
+			}
+		}
+	}
+
+	// Close drains the queued deliveries
+	bus.Close()
+
+	for order := 1; order <= 5; order++ {
+		if len(seen[order]) != 50 {
+			t.Fatalf("Order %d: expected 50 events, got %d", order, len(seen[order]))
+		}
+		for i, seq := range seen[order] {
+			if seq != i {
+				t.Fatalf("Order %d: events out of order: %v", order, seen[order])
+			}
+		}
+	}
+
+	if err := bus.Publish(context.Background(), orderPlaced{OrderID: 1}); !errors.Is(err, events.ErrClosed) {
+		t.Errorf("Expected ErrClosed after Close, got %v", err)
+	}
+}
+
+func TestBusFullShardBlocksOnlyThePublisher(t *testing.T) {
+	bus := events.NewBus(events.Options{Shards: 1, BufferSize: 1})
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var delivered int
+	events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		delivered++
+		return nil
+	}, events.Async())
+
+	// The first event occupies the shard, the second fills its buffer
+	publish := func(seq int) error {
+		return bus.Publish(context.Background(), orderPlaced{OrderID: 1, Seq: seq})
+	}
+	if err := publish(1); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	<-started
+	if err := publish(2); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- publish(3) }()
+
+	subscribed := make(chan struct{})
+	go func() {
+		events.Subscribe(bus, func(ctx context.Context, e orderPlaced) error { return nil })
+		close(subscribed)
+	}()
+	select {
+	case <-subscribed:
+	case <-time.After(time.Second):
+		t.Fatal("Subscribe blocked behind a publisher waiting on a full shard")
+	}
+
+	closed := make(chan struct{})
+	go func() {
+		bus.Close()
+		close(closed)
+	}()
+	select {
+	case err := <-blocked:
+		if !errors.Is(err, events.ErrClosed) {
+			t.Errorf("Expected ErrClosed for the blocked publisher, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not release the blocked publisher")
+	}
+
+	// Close drains the queued deliveries once the subscriber continues
+	close(release)
+	<-closed
+	if delivered != 2 {
+		t.Errorf("Expected 2 delivered events, got %d", delivered)
+	}
+}
+
+func TestUserServicePublishesEvents(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus(events.Options{})
+	defer bus.Close()
+
+	var published []events.Event
+	record := func(ctx context.Context, e events.Event) error {
+		published = append(published, e)
+		return nil
+	}
+	events.Subscribe(bus, func(ctx context.Context, e services.UserCreated) error { return record(ctx, e) })
+	events.Subscribe(bus, func(ctx context.Context, e services.UserUpdated) error { return record(ctx, e) })
+	events.Subscribe(bus, func(ctx context.Context, e services.UserDeleted) error { return record(ctx, e) })
+
+	service := services.NewUserService(NewFakeUserRepository(), &FakeTxManager{}, bus)
+
+	user, err := service.CreateUser(ctx, &models.User{Name: "Jane", Email: "jane@example.com"})
+	if err != nil {
+		t.Fatalf("CreateUser failed: %v", err)
+	}
+	if err := service.UpdateUser(ctx, &models.User{ID: user.ID, Name: "Jane", Email: "jane@example.org"}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+	if err := service.DeleteUser(ctx, user.ID, 0); err != nil {
+		t.Fatalf("DeleteUser failed: %v", err)
+	}
+
+	if len(published) != 3 {
+		t.Fatalf("Expected 3 events, got %d", len(published))
+	}
+	if e, ok := published[0].(services.UserCreated); !ok || e.User.Email != "jane@example.com" {
+		t.Errorf("Expected UserCreated, got %#v", published[0])
+	}
+	if e, ok := published[1].(services.UserUpdated); !ok || len(e.Changed) != 1 || e.Changed[0] != "email" {
+		t.Errorf("Expected UserUpdated with email changed, got %#v", published[1])
+	}
+	if e, ok := published[2].(services.UserDeleted); !ok || e.AggregateID() != strconv.FormatInt(user.ID, 10) {
+		t.Errorf("Expected UserDeleted, got %#v", published[2])
+	}
+}
@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+func TestErrorMiddlewareMapsErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	utils.RegisterErrorCode(utils.ErrorCode{Code: "QUOTA_EXCEEDED", Status: http.StatusTooManyRequests, Message: "quota exceeded"})
+
+	validation := utils.NewValidationErrors().Add("email", "is required")
+
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   string
+	}{
+		{"validation", validation, http.StatusUnprocessableEntity, "VALIDATION_ERROR"},
+		{"single validation error", utils.ValidationError{Field: "name", Message: "is too long"}, http.StatusUnprocessableEntity, "VALIDATION_ERROR"},
+		{"not found", utils.NewNotFoundError("user", 1), http.StatusNotFound, utils.CodeNotFound},
+		{"wrapped conflict", fmt.Errorf("create: %w", utils.NewFieldConflictError("email", "user with this email already exists")), http.StatusConflict, utils.CodeConflict},
+		{"unauthorized", utils.NewUnauthorizedError("token expired"), http.StatusUnauthorized, utils.CodeUnauthorized},
+		{"forbidden", utils.NewForbiddenError("admins only"), http.StatusForbidden, utils.CodeForbidden},
+		{"registered code", utils.NewAppError("QUOTA_EXCEEDED", "too many users"), http.StatusTooManyRequests, "QUOTA_EXCEEDED"},
+		{"database code", utils.NewAppErrorWithCause(utils.CodeDeadlock, "retry", errors.New("40P01")), http.StatusServiceUnavailable, utils.CodeDeadlock},
+		{"unregistered code", utils.NewAppError("SOMETHING_ODD", "odd"), http.StatusInternalServerError, utils.CodeInternal},
+		{"unknown", errors.New("dial tcp 10.0.0.5:5432: connection refused"), http.StatusInternalServerError, utils.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(middleware.ErrorMiddleware())
+			router.GET("/", func(c *gin.Context) { c.Error(tt.err) })
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+			var body response.Response
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+			if body.Success || body.Code != tt.code {
+				t.Errorf("Expected failure with code %s, got %+v", tt.code, body)
+			}
+		})
+	}
+}
+
+func TestErrorMiddlewareSanitizesUnknownErrors(t *testing.T) {
+	router := newUserTestRouter(t, withUserService(&MockUserService{
+		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
+			return nil, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+		},
+	}))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/users/1", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected a database outage to be a 500, got %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), "10.0.0.5") {
+		t.Errorf("Internal details leaked: %s", w.Body.String())
+	}
+}
+
+func TestCreateUserConflict(t *testing.T) {
+	router := newUserTestRouter(t, withUserService(&MockUserService{
+		CreateUserFunc: func(ctx context.Context, user *models.User) (*models.User, error) {
+			return nil, utils.NewFieldConflictError("email", "user with this email already exists")
+		},
+	}))
+
+	w := sendUserRequest(router, http.MethodPost, "/api/v1/users", "application/json", `{"name":"Jane","email":"jane@example.com"}`)
+
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+	var body struct {
+		Details map[string]string `json:"details"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body.Details["field"] != "email" {
+		t.Errorf("Expected conflicting field email, got %v", body.Details)
+	}
+}
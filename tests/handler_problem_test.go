@@ -0,0 +1,109 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/handlers/response"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// newProblemTestRouter answers GET /fail with err in format
+func newProblemTestRouter(format response.ErrorFormat, err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorFormatMiddleware(format), middleware.ErrorMiddleware())
+	router.GET("/fail", func(c *gin.Context) { c.Error(err) })
+	router.GET("/private", middleware.AuthMiddleware(), func(c *gin.Context) {})
+	return router
+}
+
+func decodeProblem(t *testing.T, w *httptest.ResponseRecorder) response.Problem {
+	t.Helper()
+	if ct := w.Header().Get("Content-Type"); ct != response.ProblemContentType {
+		t.Fatalf("Expected Content-Type %s, got %s", response.ProblemContentType, ct)
+	}
+	var problem response.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("Failed to unmarshal problem: %v", err)
+	}
+	return problem
+}
+
+func TestProblemDetailsForValidationErrors(t *testing.T) {
+	verrs := utils.NewValidationErrors().Add("email", "must be a valid email")
+	router := newProblemTestRouter(response.ErrorFormat{Problem: true}, verrs)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/fail", nil))
+
+	problem := decodeProblem(t, w)
+	if problem.Status != http.StatusUnprocessableEntity || w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status 422, got %d (body %d)", w.Code, problem.Status)
+	}
+	if problem.Type != "about:blank" || problem.Title != "Unprocessable Entity" {
+		t.Errorf("Expected about:blank with the status text, got %q %q", problem.Type, problem.Title)
+	}
+	if problem.Instance != "/fail" || problem.Code != utils.CodeValidation {
+		t.Errorf("Unexpected instance or code: %+v", problem)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" {
+		t.Errorf("Expected per-field errors, got %+v", problem.Errors)
+	}
+}
+
+func TestProblemDetailsTypeFromCode(t *testing.T) {
+	router := newProblemTestRouter(response.ErrorFormat{
+		Problem:     true,
+		TypeBaseURL: "https://example.com/problems/",
+	}, utils.NewFieldConflictError("email", "user with this email already exists"))
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/fail", nil))
+
+	problem := decodeProblem(t, w)
+	if problem.Type != "https://example.com/problems/conflict" {
+		t.Errorf("Expected type from the code, got %s", problem.Type)
+	}
+	if problem.Title != "resource already exists" || problem.Detail != "user with this email already exists" {
+		t.Errorf("Unexpected title or detail: %+v", problem)
+	}
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" {
+		t.Errorf("Expected the conflicting field, got %+v", problem.Errors)
+	}
+}
+
+func TestProblemDetailsSelectedByAccept(t *testing.T) {
+	router := newProblemTestRouter(response.ErrorFormat{}, utils.NewNotFoundError("user", 1))
+
+	// The envelope stays the default
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/fail", nil))
+	var envelope response.Response
+	json.Unmarshal(w.Body.Bytes(), &envelope)
+	if envelope.Success || envelope.Error == "" || envelope.Code != utils.CodeNotFound {
+		t.Errorf("Expected the envelope by default, got %s", w.Body.String())
+	}
+
+	req := httptest.NewRequest("GET", "/fail", nil)
+	req.Header.Set("Accept", "application/problem+json, application/json;q=0.9")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if problem := decodeProblem(t, w); problem.Status != http.StatusNotFound {
+		t.Errorf("Expected a 404 problem, got %+v", problem)
+	}
+
+	// Middleware writing errors directly follows the format too
+	req = httptest.NewRequest("GET", "/private", nil)
+	req.Header.Set("Accept", response.ProblemContentType)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if problem := decodeProblem(t, w); problem.Status != http.StatusUnauthorized || problem.Code != utils.CodeUnauthorized {
+		t.Errorf("Expected a 401 problem, got %+v", problem)
+	}
+}
@@ -0,0 +1,169 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
+	"github.com/miladev95/golang-project-structure/internal/models"
+)
+
+// newETagRouter serves the user endpoints with the real user service on a
+// fake repository holding one user at version 1
+func newETagRouter(t *testing.T) (*gin.Engine, *FakeUserRepository) {
+	t.Helper()
+
+	repo := NewFakeUserRepository()
+	if _, err := repo.Create(context.Background(), &models.User{Name: "Jane", Email: "jane@example.com"}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	return newUserTestRouter(t, withUserRepository(repo)), repo
+}
+
+func sendWithIfMatch(router *gin.Engine, method, contentType, body, ifMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/users/1", strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer test-token-123")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if ifMatch != "" {
+		req.Header.Set("If-Match", ifMatch)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetUserSetsVersionETag(t *testing.T) {
+	router, _ := newETagRouter(t)
+
+	w := sendWithIfMatch(router, http.MethodGet, "", "", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if etag := w.Header().Get("ETag"); etag != `"1"` {
+		t.Errorf(`expected ETag "1", got %s`, etag)
+	}
+}
+
+func TestUpdateUserIfMatch(t *testing.T) {
+	router, repo := newETagRouter(t)
+	body := `{"name":"Janet","email":"jane@example.com"}`
+
+	w := sendWithIfMatch(router, http.MethodPut, "application/json", body, `"1"`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if etag := w.Header().Get("ETag"); etag != `"2"` {
+		t.Errorf(`expected ETag "2", got %s`, etag)
+	}
+
+	// A second writer still holding version 1 loses
+	w = sendWithIfMatch(router, http.MethodPut, "application/json", `{"name":"John","email":"john@example.com"}`, `"1"`)
+	if w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412, got %d: %s", w.Code, w.Body.String())
+	}
+
+	stored, _ := repo.GetByID(context.Background(), 1)
+	if stored.Name != "Janet" || stored.Version != 2 {
+		t.Errorf("expected first update only, got %+v", stored)
+	}
+}
+
+func TestPatchUserIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		status  int
+	}{
+		{"matching", `"1"`, http.StatusOK},
+		{"any", "*", http.StatusOK},
+		{"one of several", `"7", "1"`, http.StatusOK},
+		{"stale", `"2"`, http.StatusPreconditionFailed},
+		{"weak", `W/"1"`, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, _ := newETagRouter(t)
+
+			w := sendWithIfMatch(router, http.MethodPatch, dtos.MergePatchContentType, `{"name":"Janet"}`, tt.ifMatch)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestDeleteUserIfMatch(t *testing.T) {
+	router, _ := newETagRouter(t)
+
+	if w := sendWithIfMatch(router, http.MethodDelete, "", "", `"3"`); w.Code != http.StatusPreconditionFailed {
+		t.Fatalf("expected status 412, got %d", w.Code)
+	}
+	if w := sendWithIfMatch(router, http.MethodDelete, "", "", `"1"`); w.Code != http.StatusNoContent {
+		t.Fatalf("expected status 204, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// racingUserRepository commits another write right after the first read,
+// between the handler's If-Match check and its write
+type racingUserRepository struct {
+	*FakeUserRepository
+	raced bool
+}
+
+func (r *racingUserRepository) GetByID(ctx context.Context, id int64) (*models.User, error) {
+	user, err := r.FakeUserRepository.GetByID(ctx, id)
+	if err != nil || r.raced {
+		return user, err
+	}
+	r.raced = true
+	other := *user
+	other.Name = "Other"
+	if err := r.FakeUserRepository.Update(ctx, &other); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func TestIfMatchLostToConcurrentWrite(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		contentType string
+		body        string
+		ifMatch     string
+		status      int
+	}{
+		{"PUT", http.MethodPut, "application/json", `{"name":"Janet","email":"jane@example.com"}`, `"1"`, http.StatusPreconditionFailed},
+		{"PATCH", http.MethodPatch, dtos.MergePatchContentType, `{"name":"Janet"}`, `"1"`, http.StatusPreconditionFailed},
+		{"DELETE", http.MethodDelete, "", "", `"1"`, http.StatusPreconditionFailed},
+		{"PUT without If-Match", http.MethodPut, "application/json", `{"name":"Janet","email":"jane@example.com"}`, "", http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &racingUserRepository{FakeUserRepository: NewFakeUserRepository()}
+			if _, err := repo.Create(context.Background(), &models.User{Name: "Jane", Email: "jane@example.com"}); err != nil {
+				t.Fatalf("Create failed: %v", err)
+			}
+			router := newUserTestRouter(t, withUserRepository(repo))
+
+			w := sendWithIfMatch(router, tt.method, tt.contentType, tt.body, tt.ifMatch)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+
+			stored, _ := repo.FakeUserRepository.GetByID(context.Background(), 1)
+			if stored == nil || stored.Name != "Other" {
+				t.Errorf("expected the concurrent write to win, got %+v", stored)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/miladev95/golang-project-structure/internal/events"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/services"
+)
+
+var patchCreatedAt = time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// newPatchRouter serves the user routes with the real user service on a
+// fake repository holding one user, and records UserUpdated events
+func newPatchRouter(t *testing.T) (*gin.Engine, *FakeUserRepository, *[]services.UserUpdated) {
+	t.Helper()
+
+	bus := events.NewBus(events.Options{})
+	t.Cleanup(func() { bus.Close() })
+	var updated []services.UserUpdated
+	events.Subscribe(bus, func(ctx context.Context, e services.UserUpdated) error {
+		updated = append(updated, e)
+		return nil
+	})
+
+	repo := NewFakeUserRepository()
+	if _, err := repo.Create(context.Background(), &models.User{Name: "Jane", Email: "jane@example.com", CreatedAt: patchCreatedAt}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	router := newUserTestRouter(t, withUserRepository(repo), withEventBus(bus))
+	return router, repo, &updated
+}
+
+func patchUser(router *gin.Engine, contentType, body string) *httptest.ResponseRecorder {
+	return sendUserRequest(router, http.MethodPatch, "/api/v1/users/1", contentType, body)
+}
+
+func decodePatchResponse(t *testing.T, w *httptest.ResponseRecorder) dtos.PatchUserResponse {
+	t.Helper()
+	var body struct {
+		Data dtos.PatchUserResponse `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	return body.Data
+}
+
+func TestPatchUserMergePatch(t *testing.T) {
+	router, repo, updated := newPatchRouter(t)
+
+	w := patchUser(router, dtos.MergePatchContentType, `{"email":"jane@example.org"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	got := decodePatchResponse(t, w)
+	if got.Name != "Jane" || got.Email != "jane@example.org" {
+		t.Errorf("expected only email patched, got %+v", got)
+	}
+	if !reflect.DeepEqual(got.Changed, []string{"email"}) {
+		t.Errorf("expected changed [email], got %v", got.Changed)
+	}
+
+	stored, _ := repo.GetByID(context.Background(), 1)
+	if stored.Name != "Jane" || stored.Email != "jane@example.org" || !stored.CreatedAt.Equal(patchCreatedAt) {
+		t.Errorf("expected stored user to keep untouched fields, got %+v", stored)
+	}
+	if len(*updated) != 1 || !reflect.DeepEqual((*updated)[0].Changed, []string{"email"}) {
+		t.Errorf("expected one UserUpdated for email, got %+v", *updated)
+	}
+}
+
+func TestPatchUserJSONPatch(t *testing.T) {
+	router, _, _ := newPatchRouter(t)
+
+	body := `[{"op":"test","path":"/name","value":"Jane"},{"op":"replace","path":"/name","value":"Janet"}]`
+	w := patchUser(router, dtos.JSONPatchContentType, body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	got := decodePatchResponse(t, w)
+	if got.Name != "Janet" || !reflect.DeepEqual(got.Changed, []string{"name"}) {
+		t.Errorf("expected name patched, got %+v", got)
+	}
+}
+
+func TestPatchUserNoChanges(t *testing.T) {
+	router, _, updated := newPatchRouter(t)
+
+	w := patchUser(router, "application/json", `{"name":"Jane"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := decodePatchResponse(t, w); got.Changed == nil || len(got.Changed) != 0 {
+		t.Errorf("expected empty changed list, got %v", got.Changed)
+	}
+	if len(*updated) != 0 {
+		t.Errorf("expected no UserUpdated event, got %+v", *updated)
+	}
+}
+
+func TestPatchUserErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"failed test operation", dtos.JSONPatchContentType, `[{"op":"test","path":"/name","value":"John"}]`, http.StatusConflict},
+		{"missing path", dtos.JSONPatchContentType, `[{"op":"replace","path":"/id","value":2}]`, http.StatusConflict},
+		{"malformed JSON patch", dtos.JSONPatchContentType, `{"op":"replace"}`, http.StatusBadRequest},
+		{"malformed merge patch", dtos.MergePatchContentType, `{"name":`, http.StatusBadRequest},
+		{"read-only field", dtos.MergePatchContentType, `{"id":2}`, http.StatusBadRequest},
+		{"removed required field", dtos.MergePatchContentType, `{"name":null}`, http.StatusUnprocessableEntity},
+		{"invalid email", dtos.MergePatchContentType, `{"email":"nope"}`, http.StatusUnprocessableEntity},
+		{"unsupported media type", "text/plain", `{}`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, repo, _ := newPatchRouter(t)
+
+			w := patchUser(router, tt.contentType, tt.body)
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+
+			stored, _ := repo.GetByID(context.Background(), 1)
+			if stored.Name != "Jane" || stored.Email != "jane@example.com" {
+				t.Errorf("expected user unchanged, got %+v", stored)
+			}
+		})
+	}
+}
+
+func TestContentTypeMiddlewareOnlyAcceptsPatchTypesForPatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ContentTypeMiddleware())
+	router.POST("/users", func(c *gin.Context) { c.Status(http.StatusCreated) })
+
+	for contentType, status := range map[string]int{
+		"application/json; charset=utf-8": http.StatusCreated,
+		dtos.MergePatchContentType:        http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != status {
+			t.Errorf("%s: expected status %d, got %d", contentType, status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/dtos"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+func TestCreateUserRequestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		req    dtos.CreateUserRequest
+		fields []string
+	}{
+		{"valid", dtos.CreateUserRequest{Name: "Jane", Email: "jane@example.com"}, nil},
+		{"all missing", dtos.CreateUserRequest{}, []string{"name", "email"}},
+		{"blank name", dtos.CreateUserRequest{Name: "   ", Email: "jane@example.com"}, []string{"name"}},
+		{"bad email", dtos.CreateUserRequest{Name: "Jane", Email: "nope"}, []string{"email"}},
+		{"name too long", dtos.CreateUserRequest{Name: strings.Repeat("a", 256), Email: "x"}, []string{"name", "email"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+
+			var verrs *utils.ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("expected ValidationErrors, got %v", err)
+			}
+			if len(verrs.Errors) != len(tt.fields) {
+				t.Fatalf("expected %d errors, got %+v", len(tt.fields), verrs.Errors)
+			}
+			for i, field := range tt.fields {
+				if verrs.Errors[i].Field != field {
+					t.Errorf("error %d: expected field %q, got %q", i, field, verrs.Errors[i].Field)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateUserRequestTrims(t *testing.T) {
+	req := dtos.CreateUserRequest{Name: "  Jane ", Email: " jane@example.com "}
+	if err := req.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if req.Name != "Jane" || req.Email != "jane@example.com" {
+		t.Errorf("expected trimmed fields, got %q %q", req.Name, req.Email)
+	}
+}
+
+func TestCreateUserReportsEveryInvalidField(t *testing.T) {
+	called := false
+	router := newUserTestRouter(t, withUserService(&MockUserService{
+		CreateUserFunc: func(ctx context.Context, user *models.User) (*models.User, error) {
+			called = true
+			return user, nil
+		},
+	}))
+
+	w := sendUserRequest(router, http.MethodPost, "/api/v1/users", "application/json", `{"name":"","email":"nope"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+	if called {
+		t.Error("service must not be called with invalid input")
+	}
+
+	var body struct {
+		Code    string                  `json:"code"`
+		Details []utils.ValidationError `json:"details"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if body.Code != utils.CodeValidation {
+		t.Errorf("expected code %s, got %s", utils.CodeValidation, body.Code)
+	}
+	if len(body.Details) != 2 || body.Details[0].Field != "name" || body.Details[1].Field != "email" {
+		t.Errorf("expected name and email errors, got %+v", body.Details)
+	}
+}
+
+func TestCreateUserIgnoresServerFields(t *testing.T) {
+	var got *models.User
+	router := newUserTestRouter(t, withUserService(&MockUserService{
+		CreateUserFunc: func(ctx context.Context, user *models.User) (*models.User, error) {
+			got = user
+			return user, nil
+		},
+	}))
+
+	body := `{"id":99,"name":"Jane","email":"jane@example.com","created_at":"2020-01-01T00:00:00Z"}`
+	w := sendUserRequest(router, http.MethodPost, "/api/v1/users", "application/json", body)
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if got.ID != 0 || !got.CreatedAt.IsZero() {
+		t.Errorf("expected id and created_at to be ignored, got %+v", got)
+	}
+}
+
+func TestUpdateUserValidatesRequest(t *testing.T) {
+	router := newUserTestRouter(t, withUserService(&MockUserService{}))
+
+	w := sendUserRequest(router, http.MethodPut, "/api/v1/users/1", "application/json", `{"name":"Jane"}`)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -9,18 +9,19 @@ import (
 	"testing"
 	"time"
 
-	"github.com/gin-gonic/gin"
-	handlers "github.com/miladev95/golang-project-structure/internal/handlers/http"
 	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
 )
 
 // MockUserService implements services.UserService for testing
 type MockUserService struct {
-	GetUserFunc     func(ctx context.Context, id int64) (*models.User, error)
-	GetAllUsersFunc func(ctx context.Context) ([]models.User, error)
-	CreateUserFunc  func(ctx context.Context, user *models.User) (*models.User, error)
-	UpdateUserFunc  func(ctx context.Context, user *models.User) error
-	DeleteUserFunc  func(ctx context.Context, id int64) error
+	GetUserFunc    func(ctx context.Context, id int64) (*models.User, error)
+	ListUsersFunc  func(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error)
+	CreateUserFunc func(ctx context.Context, user *models.User) (*models.User, error)
+	UpdateUserFunc func(ctx context.Context, user *models.User) error
+	PatchUserFunc  func(ctx context.Context, id, version int64, patch func(*models.User) error) (*models.User, []string, error)
+	DeleteUserFunc func(ctx context.Context, id, version int64) error
 }
 
 func (m *MockUserService) GetUser(ctx context.Context, id int64) (*models.User, error) {
@@ -30,11 +31,11 @@ func (m *MockUserService) GetUser(ctx context.Context, id int64) (*models.User,
 	return nil, errors.New("GetUserFunc not implemented")
 }
 
-func (m *MockUserService) GetAllUsers(ctx context.Context) ([]models.User, error) {
-	if m.GetAllUsersFunc != nil {
-		return m.GetAllUsersFunc(ctx)
+func (m *MockUserService) ListUsers(ctx context.Context, query repositories.UserQuery) ([]models.User, int64, error) {
+	if m.ListUsersFunc != nil {
+		return m.ListUsersFunc(ctx, query)
 	}
-	return nil, errors.New("GetAllUsersFunc not implemented")
+	return nil, 0, errors.New("ListUsersFunc not implemented")
 }
 
 func (m *MockUserService) CreateUser(ctx context.Context, user *models.User) (*models.User, error) {
@@ -51,19 +52,22 @@ func (m *MockUserService) UpdateUser(ctx context.Context, user *models.User) err
 	return errors.New("UpdateUserFunc not implemented")
 }
 
-func (m *MockUserService) DeleteUser(ctx context.Context, id int64) error {
+func (m *MockUserService) PatchUser(ctx context.Context, id, version int64, patch func(*models.User) error) (*models.User, []string, error) {
+	if m.PatchUserFunc != nil {
+		return m.PatchUserFunc(ctx, id, version, patch)
+	}
+	return nil, nil, errors.New("PatchUserFunc not implemented")
+}
+
+func (m *MockUserService) DeleteUser(ctx context.Context, id, version int64) error {
 	if m.DeleteUserFunc != nil {
-		return m.DeleteUserFunc(ctx, id)
+		return m.DeleteUserFunc(ctx, id, version)
 	}
 	return errors.New("DeleteUserFunc not implemented")
 }
 
 // TestGetUserSuccess tests successful GetUser API call
 func TestGetUserSuccess(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-
 	expectedUser := &models.User{
 		ID:        1,
 		Name:      "John Doe",
@@ -81,8 +85,7 @@ func TestGetUserSuccess(t *testing.T) {
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(t, withUserService(mockService))
 
 	// Execute
 	req, _ := http.NewRequest("GET", "/api/v1/users/1", nil)
@@ -126,18 +129,13 @@ func TestGetUserSuccess(t *testing.T) {
 
 // TestGetUserInvalidID tests GetUser with invalid ID format
 func TestGetUserInvalidID(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-
 	mockService := &MockUserService{
 		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
 			return nil, errors.New("user not found")
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(t, withUserService(mockService))
 
 	// Test cases with invalid IDs (excluding empty string as Gin routing won't match it)
 	invalidIDs := []string{"abc", "12.34", "!@#$"}
@@ -173,18 +171,13 @@ func TestGetUserInvalidID(t *testing.T) {
 
 // TestGetUserNotFound tests GetUser when user doesn't exist
 func TestGetUserNotFound(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-
 	mockService := &MockUserService{
 		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
-			return nil, errors.New("user not found")
+			return nil, utils.NewNotFoundError("user", id)
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(t, withUserService(mockService))
 
 	// Execute
 	req, _ := http.NewRequest("GET", "/api/v1/users/999", nil)
@@ -213,10 +206,6 @@ func TestGetUserNotFound(t *testing.T) {
 
 // TestGetUserMultipleUsers tests GetUser with different user IDs
 func TestGetUserMultipleUsers(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-
 	users := map[int64]*models.User{
 		1: {
 			ID:    1,
@@ -244,8 +233,7 @@ func TestGetUserMultipleUsers(t *testing.T) {
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(t, withUserService(mockService))
 
 	// Test fetching multiple users
 	for userID, expectedUser := range users {
@@ -273,10 +261,6 @@ func TestGetUserMultipleUsers(t *testing.T) {
 
 // TestGetUserContextCancellation tests GetUser with cancelled context
 func TestGetUserContextCancellation(t *testing.T) {
-	// Setup
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
-
 	mockService := &MockUserService{
 		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
 			// Simulate context cancellation check
@@ -289,8 +273,7 @@ func TestGetUserContextCancellation(t *testing.T) {
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(t, withUserService(mockService))
 
 	// Execute - normal request (context won't be cancelled)
 	req, _ := http.NewRequest("GET", "/api/v1/users/1", nil)
@@ -305,8 +288,6 @@ func TestGetUserContextCancellation(t *testing.T) {
 
 // BenchmarkGetUser benchmarks the GetUser handler
 func BenchmarkGetUser(b *testing.B) {
-	gin.SetMode(gin.TestMode)
-	router := gin.New()
 
 	mockService := &MockUserService{
 		GetUserFunc: func(ctx context.Context, id int64) (*models.User, error) {
@@ -318,8 +299,7 @@ func BenchmarkGetUser(b *testing.B) {
 		},
 	}
 
-	handler := handlers.NewUserHandler(mockService)
-	router.GET("/api/v1/users/:id", handler.GetUser)
+	router := newUserTestRouter(b, withUserService(mockService))
 
 	req, _ := http.NewRequest("GET", "/api/v1/users/1", nil)
 
@@ -0,0 +1,144 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	handlers "github.com/miladev95/golang-project-structure/internal/handlers/http"
+	"github.com/miladev95/golang-project-structure/internal/health"
+)
+
+func TestHealthRegistry(t *testing.T) {
+	t.Run("all checks pass", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second, 0).
+			Register(health.Readiness, health.NewCheck("a", func(ctx context.Context) error { return nil })).
+			Register(health.Readiness, health.NewCheck("b", func(ctx context.Context) error { return nil }))
+
+		report := registry.Run(context.Background(), health.Readiness)
+		if !report.OK() {
+			t.Errorf("Expected ok report, got %+v", report)
+		}
+		if len(report.Checks) != 2 {
+			t.Errorf("Expected 2 results, got %d", len(report.Checks))
+		}
+	})
+
+	t.Run("one failing check fails the report", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second, 0).
+			Register(health.Readiness, health.NewCheck("ok", func(ctx context.Context) error { return nil })).
+			Register(health.Readiness, health.NewCheck("db", func(ctx context.Context) error { return errors.New("down") }))
+
+		report := registry.Run(context.Background(), health.Readiness)
+		if report.OK() {
+			t.Fatal("Expected failing report")
+		}
+		if report.Checks[1].Error != "down" || report.Checks[1].LastError != "down" {
+			t.Errorf("Expected error to be reported, got %+v", report.Checks[1])
+		}
+	})
+
+	t.Run("kinds are separate", func(t *testing.T) {
+		registry := health.NewRegistry(time.Second, 0).
+			Register(health.Readiness, health.NewCheck("db", func(ctx context.Context) error { return errors.New("down") }))
+
+		if report := registry.Run(context.Background(), health.Liveness); !report.OK() {
+			t.Errorf("Liveness should not run readiness checks, got %+v", report)
+		}
+	})
+
+	t.Run("times out slow checks", func(t *testing.T) {
+		registry := health.NewRegistry(10*time.Millisecond, 0).
+			Register(health.Readiness, health.NewCheck("slow", func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			}))
+
+		report := registry.Run(context.Background(), health.Readiness)
+		if report.OK() {
+			t.Error("Expected slow check to fail")
+		}
+	})
+
+	t.Run("caches results", func(t *testing.T) {
+		var calls int32
+		registry := health.NewRegistry(time.Second, time.Minute).
+			Register(health.Readiness, health.NewCheck("counted", func(ctx context.Context) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}))
+
+		registry.Run(context.Background(), health.Readiness)
+		registry.Run(context.Background(), health.Readiness)
+		if calls != 1 {
+			t.Errorf("Expected 1 call with caching, got %d", calls)
+		}
+	})
+
+	t.Run("keeps last error after recovery", func(t *testing.T) {
+		var fail int32 = 1
+		registry := health.NewRegistry(time.Second, 0).
+			Register(health.Readiness, health.NewCheck("flaky", func(ctx context.Context) error {
+				if atomic.LoadInt32(&fail) == 1 {
+					return errors.New("flaked")
+				}
+				return nil
+			}))
+
+		registry.Run(context.Background(), health.Readiness)
+		atomic.StoreInt32(&fail, 0)
+		report := registry.Run(context.Background(), health.Readiness)
+
+		result := report.Checks[0]
+		if result.Status != health.StatusOK || result.Error != "" {
+			t.Errorf("Expected recovered check, got %+v", result)
+		}
+		if result.LastError != "flaked" || result.LastErrorAt == nil {
+			t.Errorf("Expected last error to be kept, got %+v", result)
+		}
+	})
+}
+
+func TestHealthHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	registry := health.NewRegistry(time.Second, 0).
+		Register(health.Readiness, health.NewCheck("db", func(ctx context.Context) error { return errors.New("down") }))
+	handler := handlers.NewHealthHandler(registry)
+
+	router := gin.New()
+	router.GET("/livez", handler.Livez)
+	router.GET("/readyz", handler.Readyz)
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/livez", http.StatusOK},
+		{"/readyz", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", tt.path, nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+
+			var report health.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("Failed to unmarshal report: %v", err)
+			}
+		})
+	}
+}
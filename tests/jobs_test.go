@@ -0,0 +1,285 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/internal/jobs"
+)
+
+// FakeJobStore is an in-memory jobs.Store
+type FakeJobStore struct {
+	mu     sync.Mutex
+	jobs   map[int64]*jobs.Job
+	nextID int64
+}
+
+func NewFakeJobStore() *FakeJobStore {
+	return &FakeJobStore{jobs: make(map[int64]*jobs.Job), nextID: 1}
+}
+
+func (s *FakeJobStore) Create(ctx context.Context, job *jobs.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.ID = s.nextID
+	s.nextID++
+	stored := *job
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+func (s *FakeJobStore) Claim(ctx context.Context, workerID string, lockTimeout time.Duration) (*jobs.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	for id := int64(1); id < s.nextID; id++ {
+		job := s.jobs[id]
+		due := job.Status == jobs.StatusPending && !job.RunAt.After(now)
+		stale := job.Status == jobs.StatusRunning && job.LockedAt.Before(now.Add(-lockTimeout))
+		if stale && job.Attempts >= job.MaxAttempts {
+			job.Status = jobs.StatusDead
+			continue
+		}
+		if !due && !stale {
+			continue
+		}
+		job.Status = jobs.StatusRunning
+		job.Attempts++
+		job.LockedBy = workerID
+		job.LockedAt = &now
+		claimed := *job
+		return &claimed, nil
+	}
+	return nil, nil
+}
+
+func (s *FakeJobStore) Complete(ctx context.Context, job *jobs.Job) error {
+	job.Status = jobs.StatusDone
+	return s.Fail(ctx, job)
+}
+
+func (s *FakeJobStore) Fail(ctx context.Context, job *jobs.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job.LockedBy, job.LockedAt = "", nil
+	stored := *job
+	s.jobs[job.ID] = &stored
+	return nil
+}
+
+func (s *FakeJobStore) Get(id int64) jobs.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.jobs[id]
+}
+
+// noBackoff retries immediately
+func noBackoff(int) time.Duration { return 0 }
+
+func TestWorkerRunsJobs(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("success marks job done", func(t *testing.T) {
+		store := NewFakeJobStore()
+		var got struct{ Email string }
+		worker, _ := jobs.NewWorker(store, []jobs.Registration{{
+			Name: "send_email",
+			Handler: jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error {
+				return job.Decode(&got)
+			}),
+		}}, jobs.Options{})
+
+		job, err := jobs.NewQueue(store, 3).Enqueue(ctx, "send_email", map[string]string{"email": "jane@example.com"})
+		if err != nil {
+			t.Fatalf("Enqueue failed: %v", err)
+		}
+
+		if ran, err := worker.RunOnce(ctx); !ran || err != nil {
+			t.Fatalf("Expected job to run, got ran=%t err=%v", ran, err)
+		}
+		if got.Email != "jane@example.com" {
+			t.Errorf("Expected decoded payload, got %+v", got)
+		}
+		if stored := store.Get(job.ID); stored.Status != jobs.StatusDone || stored.Attempts != 1 {
+			t.Errorf("Expected done after 1 attempt, got %s after %d", stored.Status, stored.Attempts)
+		}
+	})
+
+	t.Run("failures retry then dead-letter", func(t *testing.T) {
+		store := NewFakeJobStore()
+		worker, _ := jobs.NewWorker(store, []jobs.Registration{{
+			Name: "flaky",
+			Handler: jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error {
+				return errors.New("smtp unavailable")
+			}),
+		}}, jobs.Options{Backoff: noBackoff})
+
+		job, _ := jobs.NewQueue(store, 5).Enqueue(ctx, "flaky", nil, jobs.MaxAttempts(2))
+
+		worker.RunOnce(ctx)
+		if stored := store.Get(job.ID); stored.Status != jobs.StatusPending || stored.LastError != "smtp unavailable" {
+			t.Fatalf("Expected pending retry with error, got %+v", stored)
+		}
+
+		worker.RunOnce(ctx)
+		if stored := store.Get(job.ID); stored.Status != jobs.StatusDead || stored.Attempts != 2 {
+			t.Fatalf("Expected dead job after 2 attempts, got %+v", stored)
+		}
+
+		if ran, _ := worker.RunOnce(ctx); ran {
+			t.Error("Dead jobs must not run again")
+		}
+	})
+
+	t.Run("backoff delays retry", func(t *testing.T) {
+		store := NewFakeJobStore()
+		worker, _ := jobs.NewWorker(store, nil, jobs.Options{
+			Backoff: func(int) time.Duration { return time.Hour },
+		})
+
+		job, _ := jobs.NewQueue(store, 5).Enqueue(ctx, "unknown", nil)
+		worker.RunOnce(ctx)
+
+		stored := store.Get(job.ID)
+		if !strings.HasPrefix(stored.LastError, jobs.ErrNoHandler.Error()) {
+			t.Errorf("Expected missing handler error, got %q", stored.LastError)
+		}
+		if stored.RunAt.Before(time.Now().Add(59 * time.Minute)) {
+			t.Errorf("Expected retry in an hour, got %v", stored.RunAt)
+		}
+		if ran, _ := worker.RunOnce(ctx); ran {
+			t.Error("Expected job to wait for its backoff")
+		}
+	})
+
+	t.Run("panics are recorded as failures", func(t *testing.T) {
+		store := NewFakeJobStore()
+		worker, _ := jobs.NewWorker(store, []jobs.Registration{{
+			Name: "boom",
+			Handler: jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error {
+				panic("nil map")
+			}),
+		}}, jobs.Options{Backoff: noBackoff})
+
+		job, _ := jobs.NewQueue(store, 5).Enqueue(ctx, "boom", nil)
+		worker.RunOnce(ctx)
+
+		if stored := store.Get(job.ID); stored.LastError != "panic: nil map" {
+			t.Errorf("Expected panic to be recorded, got %q", stored.LastError)
+		}
+	})
+
+	t.Run("stale running jobs are reclaimed", func(t *testing.T) {
+		store := NewFakeJobStore()
+		var calls int32
+		worker, _ := jobs.NewWorker(store, []jobs.Registration{{
+			Name: "rebuild",
+			Handler: jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error {
+				atomic.AddInt32(&calls, 1)
+				return nil
+			}),
+		}}, jobs.Options{LockTimeout: time.Minute})
+
+		job, _ := jobs.NewQueue(store, 5).Enqueue(ctx, "rebuild", nil)
+		// Simulate a worker that crashed after claiming the job
+		claimed, _ := store.Claim(ctx, "crashed", time.Minute)
+		lockedAt := time.Now().Add(-2 * time.Minute)
+		claimed.LockedAt = &lockedAt
+		store.mu.Lock()
+		store.jobs[job.ID] = claimed
+		store.mu.Unlock()
+
+		worker.RunOnce(ctx)
+		if stored := store.Get(job.ID); stored.Status != jobs.StatusDone || calls != 1 {
+			t.Errorf("Expected reclaimed job to complete, got %+v", stored)
+		}
+	})
+}
+
+func TestWorkerRunProcessesConcurrently(t *testing.T) {
+	store := NewFakeJobStore()
+	var done int32
+	worker, _ := jobs.NewWorker(store, []jobs.Registration{{
+		Name: "count",
+		Handler: jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error {
+			atomic.AddInt32(&done, 1)
+			return nil
+		}),
+	}}, jobs.Options{Concurrency: 4, PollInterval: 5 * time.Millisecond})
+
+	queue := jobs.NewQueue(store, 5)
+	for i := 0; i < 20; i++ {
+		queue.Enqueue(context.Background(), "count", i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(stopped)
+	}()
+
+	deadline := time.After(2 * time.Second)
+	for atomic.LoadInt32(&done) < 20 {
+		select {
+		case <-deadline:
+			t.Fatalf("Expected 20 jobs to run, got %d", done)
+		case <-time.After(5 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	<-stopped
+}
+
+func TestNewWorkerRejectsDuplicateHandlers(t *testing.T) {
+	handler := jobs.HandlerFunc(func(ctx context.Context, job *jobs.Job) error { return nil })
+	_, err := jobs.NewWorker(NewFakeJobStore(), []jobs.Registration{
+		{Name: "send_email", Handler: handler},
+		{Name: "send_email", Handler: handler},
+	}, jobs.Options{})
+	if err == nil {
+		t.Error("Expected duplicate handler error")
+	}
+}
+
+func TestStoreClaimDeadLettersExhaustedStaleJobs(t *testing.T) {
+	db := newSQLiteDB(t)
+	if err := db.AutoMigrate(&jobs.Job{}); err != nil {
+		t.Fatalf("AutoMigrate failed: %v", err)
+	}
+	store := jobs.NewStore(db)
+	ctx := context.Background()
+
+	lockedAt := time.Now().Add(-time.Hour)
+	exhausted := &jobs.Job{Name: "exhausted", Status: jobs.StatusRunning, Attempts: 3, MaxAttempts: 3, RunAt: lockedAt, LockedBy: "dead-worker", LockedAt: &lockedAt}
+	retryable := &jobs.Job{Name: "retryable", Status: jobs.StatusRunning, Attempts: 1, MaxAttempts: 3, RunAt: lockedAt.Add(time.Minute), LockedBy: "dead-worker", LockedAt: &lockedAt}
+	for _, job := range []*jobs.Job{exhausted, retryable} {
+		if err := db.Create(job).Error; err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	job, err := store.Claim(ctx, "worker-1", time.Minute)
+	if err != nil {
+		t.Fatalf("Claim failed: %v", err)
+	}
+	if job == nil || job.ID != retryable.ID || job.Attempts != 2 {
+		t.Fatalf("Expected the retryable job on its second attempt, got %+v", job)
+	}
+
+	var stored jobs.Job
+	db.First(&stored, exhausted.ID)
+	if stored.Status != jobs.StatusDead || stored.Attempts != 3 || stored.LockedAt != nil {
+		t.Errorf("Expected the exhausted job dead-lettered, got %+v", stored)
+	}
+
+	if job, err := store.Claim(ctx, "worker-1", time.Minute); err != nil || job != nil {
+		t.Errorf("Expected nothing left to claim, got %+v, %v", job, err)
+	}
+}
@@ -0,0 +1,295 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/di"
+	"github.com/miladev95/golang-project-structure/internal/messaging"
+	"github.com/miladev95/golang-project-structure/internal/outbox"
+)
+
+// FakeProcessedStore is an in-memory messaging.ProcessedStore. It is also
+// a transaction.Manager that rolls back claims made by a failing function.
+type FakeProcessedStore struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func NewFakeProcessedStore() *FakeProcessedStore {
+	return &FakeProcessedStore{processed: make(map[string]bool)}
+}
+
+func (s *FakeProcessedStore) Claim(ctx context.Context, consumer, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := consumer + "/" + eventID
+	if s.processed[key] {
+		return false, nil
+	}
+	s.processed[key] = true
+	return true, nil
+}
+
+func (s *FakeProcessedStore) Processed(consumer, eventID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[consumer+"/"+eventID]
+}
+
+func (s *FakeProcessedStore) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	snapshot := make(map[string]bool, len(s.processed))
+	for k, v := range s.processed {
+		snapshot[k] = v
+	}
+	s.mu.Unlock()
+
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		s.processed = snapshot
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func TestNewEnvelope(t *testing.T) {
+	a, err := messaging.NewEnvelope("user.created", 1, "42", map[string]string{"email": "jane@example.com"})
+	if err != nil {
+		t.Fatalf("NewEnvelope failed: %v", err)
+	}
+	b, _ := messaging.NewEnvelope("user.created", 1, "42", nil)
+
+	if a.ID == "" || a.ID == b.ID {
+		t.Errorf("Expected unique IDs, got %q and %q", a.ID, b.ID)
+	}
+	if a.Type != "user.created" || a.SchemaVersion != 1 || a.AggregateID != "42" || a.OccurredAt.IsZero() {
+		t.Errorf("Unexpected envelope %+v", a)
+	}
+
+	var payload map[string]string
+	if err := a.Decode(&payload); err != nil || payload["email"] != "jane@example.com" {
+		t.Errorf("Expected payload to decode, got %v, %v", payload, err)
+	}
+}
+
+func TestMemoryBrokerDeliversToEachGroup(t *testing.T) {
+	ctx := context.Background()
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	var mu sync.Mutex
+	got := make(map[string][]string)
+	record := func(name string) messaging.Handler {
+		return func(ctx context.Context, env messaging.Envelope) error {
+			mu.Lock()
+			defer mu.Unlock()
+			got[name] = append(got[name], env.Type)
+			return nil
+		}
+	}
+
+	broker.Subscribe("user.*", "audit", record("audit"))
+	broker.Subscribe("user.created", "mailer-1", record("mailer"))
+	// A second instance of the mailer group shares its events
+	broker.Subscribe("user.created", "mailer-1", record("mailer"))
+	broker.Subscribe("order.>", "orders", record("orders"))
+
+	for _, eventType := range []string{"user.created", "user.created", "user.deleted"} {
+		env, _ := messaging.NewEnvelope(eventType, 1, "1", nil)
+		if err := broker.Publish(ctx, env); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	if len(got["audit"]) != 3 {
+		t.Errorf("Expected audit to get every user event, got %v", got["audit"])
+	}
+	if len(got["mailer"]) != 2 {
+		t.Errorf("Expected the mailer group to get each user.created once, got %v", got["mailer"])
+	}
+	if len(got["orders"]) != 0 {
+		t.Errorf("Expected no order events, got %v", got["orders"])
+	}
+}
+
+func TestMemoryBrokerRedeliversFailedEvents(t *testing.T) {
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	deliveries := 0
+	broker.Subscribe("user.created", "flaky", func(ctx context.Context, env messaging.Envelope) error {
+		deliveries++
+		if deliveries < 2 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+
+	env, _ := messaging.NewEnvelope("user.created", 1, "1", nil)
+	broker.Publish(context.Background(), env)
+
+	if deliveries != 2 {
+		t.Errorf("Expected a redelivery after the failure, got %d deliveries", deliveries)
+	}
+}
+
+func TestIdempotentSkipsHandledEvents(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeProcessedStore()
+
+	calls := 0
+	fail := true
+	handler := messaging.Idempotent(store, store, "crm-sync", func(ctx context.Context, env messaging.Envelope) error {
+		calls++
+		if fail {
+			return errors.New("crm unavailable")
+		}
+		return nil
+	})
+
+	env, _ := messaging.NewEnvelope("user.created", 1, "1", nil)
+	if err := handler(ctx, env); err == nil {
+		t.Fatal("Expected handler error")
+	}
+
+	// A failed event is not recorded, so the redelivery runs the handler
+	fail = false
+	if err := handler(ctx, env); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if err := handler(ctx, env); err != nil {
+		t.Fatalf("Handler failed: %v", err)
+	}
+	if calls != 2 {
+		t.Errorf("Expected the duplicate to be skipped, got %d calls", calls)
+	}
+
+	if !store.Processed("crm-sync", env.ID) || store.Processed("other-group", env.ID) {
+		t.Error("Processed events must be tracked per consumer")
+	}
+}
+
+func TestOutboxPublisherPublishesEnvelopes(t *testing.T) {
+	ctx := context.Background()
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	var got []messaging.Envelope
+	broker.Subscribe(">", "all", func(ctx context.Context, env messaging.Envelope) error {
+		got = append(got, env)
+		return nil
+	})
+	publisher := messaging.NewOutboxPublisher(broker)
+
+	env, _ := messaging.NewEnvelope("user.created", 1, "7", map[string]string{"name": "Jane"})
+	wrapped, _ := outbox.NewMessage("user.created", "7", env)
+	wrapped.ID = 1
+	legacy, _ := outbox.NewMessage("user.deleted", "7", map[string]int64{"id": 7})
+	legacy.ID = 2
+
+	for _, msg := range []*outbox.Message{wrapped, legacy} {
+		if err := publisher.Publish(ctx, msg); err != nil {
+			t.Fatalf("Publish failed: %v", err)
+		}
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 envelopes, got %d", len(got))
+	}
+	if got[0].ID != env.ID || got[0].Type != "user.created" {
+		t.Errorf("Expected the stored envelope to be published as is, got %+v", got[0])
+	}
+	if got[1].ID != "outbox-2" || got[1].Type != "user.deleted" || got[1].SchemaVersion != 1 {
+		t.Errorf("Expected legacy payload to be wrapped, got %+v", got[1])
+	}
+}
+
+func TestConsumersRun(t *testing.T) {
+	broker := messaging.NewMemoryBroker()
+	defer broker.Close()
+
+	handled := make(chan string, 2)
+	store := NewFakeProcessedStore()
+	consumers, err := messaging.NewConsumers(broker, store, store, []messaging.Registration{{
+		Subject: "user.created",
+		Group:   "welcome-mail",
+		Handler: func(ctx context.Context, env messaging.Envelope) error {
+			handled <- env.ID
+			return nil
+		},
+	}})
+	if err != nil {
+		t.Fatalf("NewConsumers failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumers.Run(ctx) }()
+
+	env, _ := messaging.NewEnvelope("user.created", 1, "1", nil)
+	deadline := time.After(time.Second)
+	for delivered := false; !delivered; {
+		// Publish until Run has subscribed; duplicates are skipped
+		broker.Publish(context.Background(), env)
+		select {
+		case id := <-handled:
+			if id != env.ID {
+				t.Fatalf("Expected %s, got %s", env.ID, id)
+			}
+			delivered = true
+		case <-deadline:
+			t.Fatal("Timed out waiting for delivery")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run returned %v", err)
+	}
+
+	broker.Publish(context.Background(), env)
+	if len(handled) != 0 {
+		t.Error("Expected no deliveries after Run returned")
+	}
+}
+
+func TestConsumersRejectDuplicateGroups(t *testing.T) {
+	noop := func(ctx context.Context, env messaging.Envelope) error { return nil }
+	_, err := messaging.NewConsumers(messaging.NewMemoryBroker(), &FakeTxManager{}, NewFakeProcessedStore(), []messaging.Registration{
+		{Subject: "user.created", Group: "sync", Handler: noop},
+		{Subject: "user.deleted", Group: "sync", Handler: noop},
+	})
+	if err == nil {
+		t.Error("Expected error for duplicate group")
+	}
+}
+
+func TestOutboxLogPublisherDoesNotConnectToBroker(t *testing.T) {
+	cfg := config.LoadConfig()
+	cfg.Messaging.Driver = "nats"
+	cfg.Messaging.NATSURL = "nats://127.0.0.1:1"
+
+	resolve := func(publisher string) error {
+		cfg.Outbox.Publisher = publisher
+		container := di.NewContainer()
+		if err := container.Setup(cfg); err != nil {
+			t.Fatalf("Setup failed: %v", err)
+		}
+		_, err := di.Resolve[outbox.Publisher](container)
+		return err
+	}
+
+	if err := resolve("log"); err != nil {
+		t.Errorf("Expected the log publisher without a broker, got %v", err)
+	}
+	if err := resolve("broker"); err == nil {
+		t.Error("Expected the broker publisher to need a reachable broker")
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+)
+
+// TestSQLMigrationsMatchGoMigrations keeps migrations/*.sql, which
+// operators apply with golang-migrate, in step with the Go migrations
+func TestSQLMigrationsMatchGoMigrations(t *testing.T) {
+	latest := config.LatestMigrationVersion()
+	for version := 1; version <= latest+1; version++ {
+		for _, direction := range []string{"up", "down"} {
+			pattern := filepath.Join("..", "migrations", fmt.Sprintf("%03d_*.%s.sql", version, direction))
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				t.Fatalf("Glob failed: %v", err)
+			}
+
+			switch {
+			case version > latest && len(matches) != 0:
+				t.Errorf("SQL migration %v has no Go migration", matches)
+			case version <= latest && len(matches) != 1:
+				t.Errorf("Expected one %s SQL migration for version %d, got %v", direction, version, matches)
+			}
+		}
+	}
+}
+
+// newSQLiteDB opens an in-memory SQLite database private to the test
+func newSQLiteDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatalf("Failed to open SQLite: %v", err)
+	}
+	sqlDB, _ := db.DB()
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func TestRunMigrationsLeavesGolangMigrateTableAlone(t *testing.T) {
+	db := newSQLiteDB(t)
+	db.Exec("CREATE TABLE schema_migrations (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)")
+	db.Exec("INSERT INTO schema_migrations (version, dirty) VALUES (?, false)", config.LatestMigrationVersion())
+
+	if err := config.RunMigrations(db); err != nil {
+		t.Fatalf("RunMigrations failed: %v", err)
+	}
+	if db.Migrator().HasColumn("schema_migrations", "name") || db.Migrator().HasTable("app_schema_migrations") {
+		t.Error("Expected golang-migrate's table to be left as it was")
+	}
+	if db.Migrator().HasTable("users") {
+		t.Error("Expected Go migrations not to run on a golang-migrate database")
+	}
+	if version, err := config.CurrentMigrationVersion(db); err != nil || version != config.LatestMigrationVersion() {
+		t.Errorf("Expected golang-migrate's version %d, got %d, %v", config.LatestMigrationVersion(), version, err)
+	}
+	if err := config.RollbackMigrations(db); err == nil {
+		t.Error("Expected rollback to refuse a golang-migrate database")
+	}
+
+	db.Exec("UPDATE schema_migrations SET dirty = true")
+	if _, err := config.CurrentMigrationVersion(db); err == nil {
+		t.Error("Expected an error for a dirty golang-migrate version")
+	}
+}
+
+func TestRunMigrationsRenamesLegacyTable(t *testing.T) {
+	db := newSQLiteDB(t)
+	db.Exec("CREATE TABLE schema_migrations (version integer PRIMARY KEY, name varchar(255) NOT NULL, applied_at datetime)")
+	db.Exec("INSERT INTO schema_migrations (version, name) VALUES (1, 'create_users_table')")
+	// Migration 1 is recorded but users is missing, so migration 2 fails after the rename
+	_ = config.RunMigrations(db)
+
+	if db.Migrator().HasTable("schema_migrations") || !db.Migrator().HasTable("app_schema_migrations") {
+		t.Fatal("Expected the legacy table to be renamed to app_schema_migrations")
+	}
+	var count int64
+	db.Table("app_schema_migrations").Where("version = 1").Count(&count)
+	if count != 1 {
+		t.Error("Expected the applied versions to move with the table")
+	}
+}
@@ -0,0 +1,224 @@
+package tests
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/internal/outbox"
+)
+
+// FakeOutboxStore is an in-memory outbox.Store
+type FakeOutboxStore struct {
+	mu       sync.Mutex
+	messages []*outbox.Message
+}
+
+func (s *FakeOutboxStore) Add(t *testing.T, topic, aggregateID string, payload interface{}) *outbox.Message {
+	t.Helper()
+	msg, err := outbox.NewMessage(topic, aggregateID, payload)
+	if err != nil {
+		t.Fatalf("NewMessage failed: %v", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg.ID = int64(len(s.messages) + 1)
+	s.messages = append(s.messages, msg)
+	return msg
+}
+
+func (s *FakeOutboxStore) Claim(ctx context.Context, limit int, lease time.Duration) ([]*outbox.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var claimed []*outbox.Message
+	for _, msg := range s.messages {
+		if len(claimed) == limit {
+			break
+		}
+		if msg.Status != outbox.StatusPending || msg.NextAttemptAt.After(now) {
+			continue
+		}
+		msg.NextAttemptAt = now.Add(lease)
+		// The relay works on copies, like rows read from the database
+		copied := *msg
+		claimed = append(claimed, &copied)
+	}
+	return claimed, nil
+}
+
+func (s *FakeOutboxStore) Save(ctx context.Context, messages []*outbox.Message) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, saved := range messages {
+		for _, msg := range s.messages {
+			if msg.ID == saved.ID {
+				*msg = *saved
+			}
+		}
+	}
+	return nil
+}
+
+func TestRelayPublishesPendingMessages(t *testing.T) {
+	ctx := context.Background()
+	store := &FakeOutboxStore{}
+	created := store.Add(t, "user.created", "1", map[string]string{"email": "jane@example.com"})
+	updated := store.Add(t, "user.updated", "1", map[string]string{"name": "Jane"})
+
+	publisher := outbox.NewMemoryPublisher()
+	relay := outbox.NewRelay(store, publisher, outbox.Options{})
+
+	if n, err := relay.RelayOnce(ctx); n != 2 || err != nil {
+		t.Fatalf("Expected 2 messages relayed, got %d, %v", n, err)
+	}
+
+	published := publisher.Messages()
+	if len(published) != 2 || published[0].Topic != "user.created" || published[1].Topic != "user.updated" {
+		t.Fatalf("Expected messages in order, got %+v", published)
+	}
+	for _, msg := range []*outbox.Message{created, updated} {
+		if msg.Status != outbox.StatusSent || msg.SentAt == nil {
+			t.Errorf("Expected message %d to be marked sent, got %+v", msg.ID, msg)
+		}
+	}
+
+	if n, _ := relay.RelayOnce(ctx); n != 0 {
+		t.Errorf("Sent messages must not be relayed again, got %d", n)
+	}
+}
+
+func TestRelayRetriesFailedMessages(t *testing.T) {
+	ctx := context.Background()
+	store := &FakeOutboxStore{}
+	msg := store.Add(t, "user.created", "1", nil)
+
+	fail := true
+	publisher := outbox.PublisherFunc(func(ctx context.Context, m *outbox.Message) error {
+		if fail {
+			return errors.New("broker unavailable")
+		}
+		return nil
+	})
+	relay := outbox.NewRelay(store, publisher, outbox.Options{
+		Backoff: func(int) time.Duration { return time.Hour },
+	})
+
+	relay.RelayOnce(ctx)
+	if msg.Status != outbox.StatusPending || msg.LastError != "broker unavailable" || msg.Attempts != 1 {
+		t.Fatalf("Expected pending message with error, got %+v", msg)
+	}
+	if !msg.NextAttemptAt.After(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("Expected retry after backoff, got %v", msg.NextAttemptAt)
+	}
+
+	// Due again after the backoff
+	msg.NextAttemptAt = time.Now()
+	fail = false
+	relay.RelayOnce(ctx)
+	if msg.Status != outbox.StatusSent || msg.LastError != "" || msg.Attempts != 2 {
+		t.Errorf("Expected message sent on retry, got %+v", msg)
+	}
+}
+
+func TestRelayLeasesClaimedMessages(t *testing.T) {
+	ctx := context.Background()
+	store := &FakeOutboxStore{}
+	msg := store.Add(t, "user.created", "1", nil)
+
+	// A second relay polls while the first is still publishing
+	var second int
+	var secondErr error
+	secondRelay := outbox.NewRelay(store, outbox.NewMemoryPublisher(), outbox.Options{})
+	publisher := outbox.PublisherFunc(func(ctx context.Context, m *outbox.Message) error {
+		second, secondErr = secondRelay.RelayOnce(ctx)
+		return nil
+	})
+	relay := outbox.NewRelay(store, publisher, outbox.Options{Lease: time.Minute})
+
+	if n, err := relay.RelayOnce(ctx); n != 1 || err != nil {
+		t.Fatalf("Expected 1 message relayed, got %d, %v", n, err)
+	}
+	if second != 0 || secondErr != nil {
+		t.Errorf("Expected a leased message to be skipped, got %d, %v", second, secondErr)
+	}
+	if msg.Status != outbox.StatusSent {
+		t.Errorf("Expected message saved as sent, got %+v", msg)
+	}
+
+	// A relay that crashed before saving leaves the message leased
+	crashed := store.Add(t, "user.updated", "1", nil)
+	claimed, _ := store.Claim(ctx, 10, time.Minute)
+	if len(claimed) != 1 || !crashed.NextAttemptAt.After(time.Now().Add(59*time.Second)) {
+		t.Fatalf("Expected the message leased for a minute, got %+v", crashed)
+	}
+	crashed.NextAttemptAt = time.Now()
+	if n, _ := relay.RelayOnce(ctx); n != 1 || crashed.Status != outbox.StatusSent {
+		t.Errorf("Expected the message retried after the lease, got %d, %+v", n, crashed)
+	}
+}
+
+func TestWebhookPublisher(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotSignature string
+		gotID        string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotSignature = r.Header.Get("X-Outbox-Signature")
+		gotID = r.Header.Get("X-Outbox-Message-ID")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	msg, _ := outbox.NewMessage("user.created", "7", map[string]string{"email": "jane@example.com"})
+	msg.ID = 42
+
+	publisher := outbox.NewWebhookPublisher(srv.URL, "s3cret", nil)
+	if err := publisher.Publish(context.Background(), msg); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	var body struct {
+		ID      int64             `json:"id"`
+		Topic   string            `json:"topic"`
+		Payload map[string]string `json:"payload"`
+	}
+	if err := json.Unmarshal(gotBody, &body); err != nil {
+		t.Fatalf("Failed to unmarshal webhook body: %v", err)
+	}
+	if body.ID != 42 || body.Topic != "user.created" || body.Payload["email"] != "jane@example.com" {
+		t.Errorf("Unexpected webhook body %s", gotBody)
+	}
+	if gotID != "42" {
+		t.Errorf("Expected message ID header 42, got %q", gotID)
+	}
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write(gotBody)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); gotSignature != want {
+		t.Errorf("Expected signature %s, got %s", want, gotSignature)
+	}
+}
+
+func TestWebhookPublisherFailsOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	msg, _ := outbox.NewMessage("user.deleted", "7", nil)
+	if err := outbox.NewWebhookPublisher(srv.URL, "", nil).Publish(context.Background(), msg); err == nil {
+		t.Error("Expected error for 502 response")
+	}
+}
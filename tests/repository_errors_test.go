@@ -0,0 +1,96 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+func TestTranslateErrorNotFound(t *testing.T) {
+	err := repositories.TranslateError(fmt.Errorf("query: %w", gorm.ErrRecordNotFound), "user", int64(7))
+
+	var notFound utils.NotFoundError
+	if !errors.As(err, &notFound) || notFound.Resource != "user" || notFound.ID != int64(7) {
+		t.Errorf("Expected NotFoundError for user 7, got %#v", err)
+	}
+}
+
+func TestTranslateErrorConflict(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		field string
+	}{
+		{"postgres detail", &pgconn.PgError{Code: "23505", Detail: "Key (email)=(jane@example.com) already exists.", ConstraintName: "users_email_key"}, "email"},
+		{"postgres constraint", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", TableName: "users"}, "email"},
+		{"mysql 8", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'jane@example.com' for key 'users.email'"}, "email"},
+		{"mysql index name", &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'jane@example.com' for key 'users.idx_users_email'"}, "email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repositories.TranslateError(tt.err, "user", nil)
+
+			var conflict utils.ConflictError
+			if !errors.As(err, &conflict) {
+				t.Fatalf("Expected ConflictError, got %#v", err)
+			}
+			if conflict.Field != tt.field {
+				t.Errorf("Expected field %q, got %q", tt.field, conflict.Field)
+			}
+			if conflict.Message != "user with this email already exists" {
+				t.Errorf("Unexpected message %q", conflict.Message)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code string
+	}{
+		{"postgres foreign key", &pgconn.PgError{Code: "23503"}, utils.CodeForeignKeyViolation},
+		{"mysql foreign key", &mysql.MySQLError{Number: 1452}, utils.CodeForeignKeyViolation},
+		{"postgres check", &pgconn.PgError{Code: "23514", ConstraintName: "users_name_check"}, utils.CodeCheckViolation},
+		{"mysql check", &mysql.MySQLError{Number: 3819}, utils.CodeCheckViolation},
+		{"postgres deadlock", &pgconn.PgError{Code: "40P01"}, utils.CodeDeadlock},
+		{"mysql deadlock", &mysql.MySQLError{Number: 1213}, utils.CodeDeadlock},
+		{"postgres statement timeout", &pgconn.PgError{Code: "57014"}, utils.CodeTimeout},
+		{"mysql lock wait timeout", &mysql.MySQLError{Number: 1205}, utils.CodeTimeout},
+		{"context deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), utils.CodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := repositories.TranslateError(tt.err, "user", nil)
+
+			var appErr *utils.AppError
+			if !errors.As(err, &appErr) || appErr.Code != tt.code {
+				t.Fatalf("Expected AppError %s, got %#v", tt.code, err)
+			}
+			if appErr.Err != tt.err {
+				t.Error("Expected the driver error to be kept as the cause")
+			}
+		})
+	}
+}
+
+func TestTranslateErrorPassesUnknownErrors(t *testing.T) {
+	unknown := errors.New("connection refused")
+	if err := repositories.TranslateError(unknown, "user", nil); err != unknown {
+		t.Errorf("Expected unknown errors unchanged, got %v", err)
+	}
+	if err := repositories.TranslateError(nil, "user", nil); err != nil {
+		t.Errorf("Expected nil, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	handlers "github.com/miladev95/golang-project-structure/internal/handlers/http"
+	"github.com/miladev95/golang-project-structure/internal/handlers/http/routes"
+	"github.com/miladev95/golang-project-structure/internal/scheduler"
+)
+
+// FakeSchedulerStore is an in-memory scheduler.Store shared by replicas
+type FakeSchedulerStore struct {
+	mu     sync.Mutex
+	states map[string]*scheduler.TaskState
+}
+
+func NewFakeSchedulerStore() *FakeSchedulerStore {
+	return &FakeSchedulerStore{states: make(map[string]*scheduler.TaskState)}
+}
+
+func (s *FakeSchedulerStore) Ensure(ctx context.Context, names []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, name := range names {
+		if _, ok := s.states[name]; !ok {
+			s.states[name] = &scheduler.TaskState{Name: name}
+		}
+	}
+	return nil
+}
+
+func (s *FakeSchedulerStore) Acquire(ctx context.Context, name string, tick time.Time, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[name]
+	if !ok {
+		return false, nil
+	}
+	now := time.Now()
+	if state.LastScheduledAt != nil && !state.LastScheduledAt.Before(tick) {
+		return false, nil
+	}
+	if state.LeaseUntil != nil && state.LeaseUntil.After(now) {
+		return false, nil
+	}
+	until := now.Add(ttl)
+	state.LastScheduledAt, state.LeaseHolder, state.LeaseUntil = &tick, holder, &until
+	return true, nil
+}
+
+func (s *FakeSchedulerStore) Release(ctx context.Context, name, holder string, startedAt time.Time, duration time.Duration, runErr error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state := s.states[name]
+	if state.LeaseHolder != holder {
+		return nil
+	}
+	state.LeaseHolder, state.LeaseUntil = "", nil
+	state.LastRunAt, state.LastDurationMs = &startedAt, duration.Milliseconds()
+	state.LastError = ""
+	if runErr != nil {
+		state.LastError = runErr.Error()
+	}
+	return nil
+}
+
+func (s *FakeSchedulerStore) List(ctx context.Context) ([]scheduler.TaskState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states := make([]scheduler.TaskState, 0, len(s.states))
+	for _, state := range s.states {
+		states = append(states, *state)
+	}
+	return states, nil
+}
+
+func TestSchedulerRunsEachTickOnce(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeSchedulerStore()
+	store.Ensure(ctx, []string{"purge"})
+
+	var runs int32
+	task := scheduler.Registration{
+		Name:     "purge",
+		Schedule: "*/5 * * * *",
+		Task: scheduler.TaskFunc(func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		}),
+	}
+
+	// Two replicas share the store
+	replicaA, err := scheduler.New(store, []scheduler.Registration{task}, scheduler.Options{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	replicaB, _ := scheduler.New(store, []scheduler.Registration{task}, scheduler.Options{})
+
+	tick := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ranA, _ := replicaA.RunDue(ctx, "purge", tick)
+	ranB, _ := replicaB.RunDue(ctx, "purge", tick)
+
+	if !ranA || ranB || runs != 1 {
+		t.Fatalf("Expected exactly one replica to run the tick, got A=%t B=%t runs=%d", ranA, ranB, runs)
+	}
+
+	if ran, _ := replicaB.RunDue(ctx, "purge", tick.Add(5*time.Minute)); !ran || runs != 2 {
+		t.Errorf("Expected the next tick to run, got ran=%t runs=%d", ran, runs)
+	}
+}
+
+func TestSchedulerRecordsRuns(t *testing.T) {
+	ctx := context.Background()
+	store := NewFakeSchedulerStore()
+
+	s, err := scheduler.New(store, []scheduler.Registration{
+		{Name: "fails", Schedule: "@hourly", Task: scheduler.TaskFunc(func(ctx context.Context) error {
+			return errors.New("stats table locked")
+		})},
+		{Name: "panics", Schedule: "@every 10m", Task: scheduler.TaskFunc(func(ctx context.Context) error {
+			panic("boom")
+		})},
+	}, scheduler.Options{})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	store.Ensure(ctx, []string{"fails", "panics"})
+
+	tick := time.Now()
+	s.RunDue(ctx, "fails", tick)
+	s.RunDue(ctx, "panics", tick)
+
+	tasks, err := s.Tasks(ctx)
+	if err != nil {
+		t.Fatalf("Tasks failed: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("Expected 2 tasks, got %d", len(tasks))
+	}
+
+	wantErrors := map[string]string{"fails": "stats table locked", "panics": "panic: boom"}
+	for _, task := range tasks {
+		if task.LastRunAt == nil {
+			t.Errorf("%s: expected last run time", task.Name)
+		}
+		if task.LastError != wantErrors[task.Name] {
+			t.Errorf("%s: expected error %q, got %q", task.Name, wantErrors[task.Name], task.LastError)
+		}
+		if !task.NextRunAt.After(tick) {
+			t.Errorf("%s: expected next run after now, got %v", task.Name, task.NextRunAt)
+		}
+		if task.LeaseHolder != "" {
+			t.Errorf("%s: expected lease to be released", task.Name)
+		}
+	}
+}
+
+func TestSchedulerAlignsEverySchedules(t *testing.T) {
+	ctx := context.Background()
+	s, _ := scheduler.New(NewFakeSchedulerStore(), []scheduler.Registration{
+		{Name: "stats", Schedule: "@every 15m", Task: scheduler.TaskFunc(func(ctx context.Context) error { return nil })},
+	}, scheduler.Options{})
+
+	tasks, _ := s.Tasks(ctx)
+	next := tasks[0].NextRunAt
+	if next.Minute()%15 != 0 || next.Second() != 0 {
+		t.Errorf("Expected next run on a 15 minute boundary, got %v", next)
+	}
+}
+
+func TestSchedulerRejectsInvalidRegistrations(t *testing.T) {
+	noop := scheduler.TaskFunc(func(ctx context.Context) error { return nil })
+
+	tests := []struct {
+		name          string
+		registrations []scheduler.Registration
+	}{
+		{"invalid cron", []scheduler.Registration{{Name: "a", Schedule: "61 * * * *", Task: noop}}},
+		{"duplicate name", []scheduler.Registration{
+			{Name: "a", Schedule: "@daily", Task: noop},
+			{Name: "a", Schedule: "@hourly", Task: noop},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := scheduler.New(NewFakeSchedulerStore(), tt.registrations, scheduler.Options{}); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestSchedulerHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	s, _ := scheduler.New(NewFakeSchedulerStore(), []scheduler.Registration{
+		{Name: "purge", Schedule: "@daily", Task: scheduler.TaskFunc(func(ctx context.Context) error { return nil })},
+	}, scheduler.Options{})
+
+	router := gin.New()
+	routes.RegisterAll(router, routes.NewSchedulerRouter(handlers.NewSchedulerHandler(s)))
+
+	req, _ := http.NewRequest("GET", "/admin/scheduler/tasks", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+}
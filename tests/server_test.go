@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/server"
+)
+
+// startTestServer serves srv in the background and stops it when the test ends
+func startTestServer(t *testing.T, srv *server.Server) net.Listener {
+	t.Helper()
+
+	listener, err := srv.Listen()
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	go srv.Serve(listener)
+	t.Cleanup(func() { srv.Close() })
+	return listener
+}
+
+func protoHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	})
+}
+
+func TestPublicServerAppliesTimeouts(t *testing.T) {
+	cfg := config.LoadConfig()
+	cfg.Server.ReadHeaderTimeout = 3 * time.Second
+	cfg.Server.MaxHeaderBytes = 4096
+
+	srv, err := server.NewPublic(cfg, protoHandler())
+	if err != nil {
+		t.Fatalf("NewPublic failed: %v", err)
+	}
+
+	if srv.ReadHeaderTimeout != 3*time.Second || srv.MaxHeaderBytes != 4096 {
+		t.Errorf("Expected configured limits, got %v and %d", srv.ReadHeaderTimeout, srv.MaxHeaderBytes)
+	}
+	if srv.ReadTimeout <= 0 || srv.WriteTimeout <= 0 || srv.IdleTimeout <= 0 {
+		t.Errorf("Expected non-zero default timeouts, got %+v", srv.Server)
+	}
+}
+
+func TestPublicServerRejectsOversizedHeaders(t *testing.T) {
+	cfg := config.LoadConfig()
+	cfg.Server.Host, cfg.Server.Port = "127.0.0.1", "0"
+	cfg.Server.MaxHeaderBytes = 1024
+
+	srv, _ := server.NewPublic(cfg, protoHandler())
+	listener := startTestServer(t, srv)
+
+	req, _ := http.NewRequest("GET", "http://"+listener.Addr().String()+"/", nil)
+	req.Header.Set("X-Large", strings.Repeat("a", 8192))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusRequestHeaderFieldsTooLarge {
+		t.Errorf("Expected status %d, got %d", http.StatusRequestHeaderFieldsTooLarge, resp.StatusCode)
+	}
+}
+
+func TestPublicServerUnixSocketWithH2C(t *testing.T) {
+	cfg := config.LoadConfig()
+	cfg.Server.Socket = filepath.Join(t.TempDir(), "app.sock")
+	cfg.Server.H2C = true
+
+	srv, err := server.NewPublic(cfg, protoHandler())
+	if err != nil {
+		t.Fatalf("NewPublic failed: %v", err)
+	}
+	startTestServer(t, srv)
+
+	dialSocket := func(ctx context.Context, _, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, "unix", cfg.Server.Socket)
+	}
+
+	// Prior-knowledge HTTP/2 over the socket
+	h2Client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialSocket(ctx, network, addr)
+		},
+	}}
+	// Plain HTTP/1.1 still works on the same listener
+	h1Client := &http.Client{Transport: &http.Transport{DialContext: dialSocket}}
+
+	tests := []struct {
+		name   string
+		client *http.Client
+		proto  string
+	}{
+		{"h2c", h2Client, "HTTP/2.0"},
+		{"http/1.1", h1Client, "HTTP/1.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := tt.client.Get("http://unix/")
+			if err != nil {
+				t.Fatalf("Request failed: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.Proto != tt.proto {
+				t.Errorf("Expected %s, got %s", tt.proto, resp.Proto)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/miladev95/golang-project-structure/internal/auth"
+	"github.com/miladev95/golang-project-structure/internal/config"
+	"github.com/miladev95/golang-project-structure/internal/handlers/middleware"
+	"github.com/miladev95/golang-project-structure/internal/server"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// testCert is a certificate with its key, signed by parent or self-signed
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func newTestCert(t *testing.T, commonName string, parent *testCert, isCA bool) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+
+	serial, _ := rand.Int(rand.Reader, big.NewInt(1<<62))
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: commonName, Organization: []string{"Test"}},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:              []string{"localhost"},
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.cert, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+// writeFiles writes the certificate and key and sets their modification time
+func (c *testCert) writeFiles(t *testing.T, certFile, keyFile string, modTime time.Time) {
+	t.Helper()
+	for file, data := range map[string][]byte{certFile: c.certPEM, keyFile: c.keyPEM} {
+		if err := os.WriteFile(file, data, 0o600); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatalf("Failed to set mtime of %s: %v", file, err)
+		}
+	}
+}
+
+func TestCertReloaderPicksUpRotatedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+
+	first := newTestCert(t, "first", nil, false)
+	first.writeFiles(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	reloader, err := server.NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewCertReloader failed: %v", err)
+	}
+	reloader.SetCheckInterval(0)
+
+	cert, _ := reloader.GetCertificate(nil)
+	if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "first" {
+		t.Fatalf("Expected first certificate, got %+v", cert.Leaf)
+	}
+
+	second := newTestCert(t, "second", nil, false)
+	second.writeFiles(t, certFile, keyFile, time.Now())
+
+	cert, _ = reloader.GetCertificate(nil)
+	if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "second" {
+		t.Errorf("Expected rotated certificate, got %+v", cert.Leaf)
+	}
+
+	// A broken rotation keeps the last good certificate
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(keyFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+
+	cert, _ = reloader.GetCertificate(nil)
+	if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "second" {
+		t.Errorf("Expected previous certificate after failed reload, got %+v", cert.Leaf)
+	}
+}
+
+func TestCertReloaderThrottlesChecks(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	newTestCert(t, "first", nil, false).writeFiles(t, certFile, keyFile, time.Now().Add(-time.Minute))
+
+	reloader, err := server.NewCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("NewCertReloader failed: %v", err)
+	}
+	reloader.SetCheckInterval(time.Hour)
+	reloader.GetCertificate(nil)
+
+	newTestCert(t, "second", nil, false).writeFiles(t, certFile, keyFile, time.Now())
+	if cert, _ := reloader.GetCertificate(nil); cert.Leaf.Subject.CommonName != "first" {
+		t.Errorf("Expected no check within the interval, got %s", cert.Leaf.Subject.CommonName)
+	}
+
+	reloader.SetCheckInterval(0)
+	if cert, _ := reloader.GetCertificate(nil); cert.Leaf.Subject.CommonName != "second" {
+		t.Errorf("Expected rotated certificate once the interval passed, got %s", cert.Leaf.Subject.CommonName)
+	}
+}
+
+func TestClientCAReloaderPicksUpRotatedBundle(t *testing.T) {
+	caFile := filepath.Join(t.TempDir(), "ca.crt")
+	first := newTestCert(t, "first-ca", nil, true)
+	second := newTestCert(t, "second-ca", nil, true)
+	if err := os.WriteFile(caFile, first.certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(caFile, time.Now().Add(-time.Minute), time.Now().Add(-time.Minute))
+
+	reloader, err := server.NewClientCAReloader(caFile)
+	if err != nil {
+		t.Fatalf("NewClientCAReloader failed: %v", err)
+	}
+	reloader.SetCheckInterval(0)
+
+	poolOf := func(certs ...*testCert) *x509.CertPool {
+		pool := x509.NewCertPool()
+		for _, c := range certs {
+			pool.AddCert(c.cert)
+		}
+		return pool
+	}
+	if !reloader.Pool().Equal(poolOf(first)) {
+		t.Fatal("Expected the first CA")
+	}
+
+	if err := os.WriteFile(caFile, append(first.certPEM, second.certPEM...), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if !reloader.Pool().Equal(poolOf(first, second)) {
+		t.Error("Expected both CAs after the bundle changed")
+	}
+
+	// A broken bundle keeps the last good pool
+	if err := os.WriteFile(caFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	os.Chtimes(caFile, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	if !reloader.Pool().Equal(poolOf(first, second)) {
+		t.Error("Expected previous CAs after failed reload")
+	}
+}
+
+func TestMutualTLSExposesPrincipal(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+
+	ca := newTestCert(t, "test-ca", nil, true)
+	serverCert := newTestCert(t, "localhost", ca, false)
+	clientCert := newTestCert(t, "billing-service", ca, false)
+
+	cfg := config.LoadConfig()
+	cfg.TLS.CertFile = filepath.Join(dir, "tls.crt")
+	cfg.TLS.KeyFile = filepath.Join(dir, "tls.key")
+	cfg.TLS.ClientCAFile = filepath.Join(dir, "ca.crt")
+	serverCert.writeFiles(t, cfg.TLS.CertFile, cfg.TLS.KeyFile, time.Now())
+	if err := os.WriteFile(cfg.TLS.ClientCAFile, ca.certPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tlsConfig, err := server.NewTLSConfig(cfg)
+	if err != nil {
+		t.Fatalf("NewTLSConfig failed: %v", err)
+	}
+
+	router := gin.New()
+	router.Use(middleware.ClientCertMiddleware())
+	router.GET("/whoami", func(c *gin.Context) {
+		principal, _ := auth.FromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.CommonName)
+	})
+
+	srv := httptest.NewUnstartedServer(router)
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	clientPair, err := tls.X509KeyPair(clientCert.certPEM, clientCert.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newClient := func(certs ...tls.Certificate) *http.Client {
+		return &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+			RootCAs:      roots,
+			ServerName:   "localhost",
+			Certificates: certs,
+		}}}
+	}
+
+	resp, err := newClient(clientPair).Get(srv.URL + "/whoami")
+	if err != nil {
+		t.Fatalf("Request with client certificate failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "billing-service" {
+		t.Errorf("Expected principal billing-service, got %d %q", resp.StatusCode, body)
+	}
+
+	if _, err := newClient().Get(srv.URL + "/whoami"); err == nil {
+		t.Error("Expected request without client certificate to fail")
+	}
+}
+
+func TestTLSConfigValidation(t *testing.T) {
+	cfg := config.LoadConfig()
+	cfg.TLS.CertFile = "tls.crt"
+	cfg.TLS.MinVersion = "1.4"
+	cfg.TLS.CipherSuites = []string{"TLS_RSA_WITH_RC4_128_SHA"}
+
+	var verrs *utils.ValidationErrors
+	if err := cfg.Validate(); !errors.As(err, &verrs) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, e := range verrs.Errors {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"TLS_KEY_FILE", "TLS_MIN_VERSION", "TLS_CIPHER_SUITES"} {
+		if !fields[field] {
+			t.Errorf("Expected error for %s, got %+v", field, verrs.Errors)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/miladev95/golang-project-structure/internal/events"
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/services"
+	"github.com/miladev95/golang-project-structure/internal/transaction"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// FakeTxManager is a transaction.Manager that runs functions directly and
+// counts the transactions
+type FakeTxManager struct {
+	Calls int
+}
+
+func (m *FakeTxManager) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.Calls++
+	return fn(ctx)
+}
+
+func TestDBUsesTransactionFromContext(t *testing.T) {
+	db := newLazyDB(t)
+	tx := newLazyDB(t)
+
+	ctx := context.Background()
+	if got := transaction.DB(ctx, db); got.Statement.ConnPool != db.Statement.ConnPool {
+		t.Error("Expected the base handle without a transaction in the context")
+	}
+
+	txCtx := transaction.NewContext(ctx, tx)
+	got := transaction.DB(txCtx, db)
+	if got.Statement.ConnPool != tx.Statement.ConnPool {
+		t.Error("Expected the transaction from the context")
+	}
+	if got.Statement.Context != txCtx {
+		t.Error("Expected the handle to be bound to the context")
+	}
+
+	if _, ok := transaction.FromContext(ctx); ok {
+		t.Error("Expected no transaction in a plain context")
+	}
+}
+
+func TestUserServiceUpdatesInTransaction(t *testing.T) {
+	ctx := context.Background()
+	bus := events.NewBus(events.Options{})
+	defer bus.Close()
+
+	repo := NewFakeUserRepository()
+	tx := &FakeTxManager{}
+	service := services.NewUserService(repo, tx, bus)
+
+	user, _ := service.CreateUser(ctx, &models.User{Name: "Jane", Email: "jane@example.com"})
+	user.Name = "Jane Doe"
+	if err := service.UpdateUser(ctx, user); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+
+	if tx.Calls != 1 {
+		t.Errorf("Expected the read and write to share one transaction, got %d", tx.Calls)
+	}
+}
+
+func TestUpdateUserStaleVersionConflicts(t *testing.T) {
+	bus := events.NewBus(events.Options{})
+	defer bus.Close()
+	repo := NewFakeUserRepository()
+	service := services.NewUserService(repo, &FakeTxManager{}, bus)
+	ctx := context.Background()
+
+	user, _ := service.CreateUser(ctx, &models.User{Name: "Jane", Email: "jane@example.com"})
+	if err := service.UpdateUser(ctx, &models.User{ID: user.ID, Name: "Janet", Email: user.Email}); err != nil {
+		t.Fatalf("UpdateUser failed: %v", err)
+	}
+
+	// Version 1 was read before the update above
+	err := service.UpdateUser(ctx, &models.User{ID: user.ID, Name: "John", Email: user.Email, Version: 1})
+	var conflict *utils.ConflictError
+	if !errors.As(err, &conflict) || conflict.Field != "version" {
+		t.Fatalf("expected version conflict, got %v", err)
+	}
+}
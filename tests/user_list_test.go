@@ -0,0 +1,178 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/miladev95/golang-project-structure/internal/models"
+	"github.com/miladev95/golang-project-structure/internal/repositories"
+	postgresrepo "github.com/miladev95/golang-project-structure/internal/repositories/postgres"
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+// sqlRecorder is a gorm logger that records the SQL of every statement
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+func TestUserRepositoryListSQL(t *testing.T) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=invalid"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		DryRun:               true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create dry run DB: %v", err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := postgresrepo.NewUserRepository(db)
+	_, _, err = repo.List(context.Background(), repositories.UserQuery{
+		Pagination: utils.NewPagination(3, 5, 0),
+		Sort:       utils.ParseSort("-created_at,name"),
+		Filter:     repositories.UserFilter{NameContains: "J_n%", Email: "jane@example.com", CreatedFrom: &from},
+	})
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+
+	if len(recorder.statements) != 2 {
+		t.Fatalf("expected a count and a select, got %v", recorder.statements)
+	}
+	where := `WHERE LOWER(name) LIKE '%j\_n\%%' AND email = 'jane@example.com' AND created_at >= '2024-01-01 00:00:00'`
+	if count := recorder.statements[0]; !strings.HasPrefix(count, "SELECT count(*)") || !strings.Contains(count, where) {
+		t.Errorf("unexpected count query: %s", count)
+	}
+	expected := `SELECT * FROM "users" ` + where + ` ORDER BY "created_at" DESC,"name","id" LIMIT 5 OFFSET 10`
+	if recorder.statements[1] != expected {
+		t.Errorf("expected %s, got %s", expected, recorder.statements[1])
+	}
+}
+
+func TestUserRepositoryListRejectsUnknownSort(t *testing.T) {
+	repo := postgresrepo.NewUserRepository(newLazyDB(t))
+	_, _, err := repo.List(context.Background(), repositories.UserQuery{
+		Pagination: utils.NewPagination(1, 10, 0),
+		Sort:       []utils.SortField{{Field: "password"}},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown sort field")
+	}
+}
+
+// newListRouter serves the user routes with the real user service on a fake
+// repository holding users
+func newListRouter(t *testing.T, users ...models.User) *gin.Engine {
+	t.Helper()
+
+	repo := NewFakeUserRepository()
+	for i := range users {
+		if _, err := repo.Create(context.Background(), &users[i]); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	return newUserTestRouter(t, withUserRepository(repo))
+}
+
+type userListBody struct {
+	Data []struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	} `json:"data"`
+	Pagination struct {
+		Total      int64 `json:"total"`
+		Page       int   `json:"page"`
+		PageSize   int   `json:"page_size"`
+		TotalPages int64 `json:"total_pages"`
+	} `json:"pagination"`
+	Details []utils.ValidationError `json:"details"`
+}
+
+func listUsers(t *testing.T, router *gin.Engine, query string) (int, userListBody) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users?"+query, nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body userListBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestListUsersPaginatesFiltersAndSorts(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 1, d, 0, 0, 0, 0, time.UTC) }
+	router := newListRouter(t,
+		models.User{Name: "Anna", Email: "anna@example.com", CreatedAt: day(1)},
+		models.User{Name: "Jane", Email: "jane@example.com", CreatedAt: day(2)},
+		models.User{Name: "Janet", Email: "janet@example.com", CreatedAt: day(3)},
+		models.User{Name: "Benjamin", Email: "ben@example.com", CreatedAt: day(4)},
+	)
+
+	status, body := listUsers(t, router, "")
+	if status != http.StatusOK || len(body.Data) != 4 || body.Pagination.Total != 4 || body.Pagination.Page != 1 {
+		t.Fatalf("expected all users on page 1, got %d %+v", status, body)
+	}
+
+	_, body = listUsers(t, router, "page=2&page_size=3")
+	if len(body.Data) != 1 || body.Data[0].Name != "Benjamin" || body.Pagination.TotalPages != 2 {
+		t.Errorf("expected the last user on page 2 of 2, got %+v", body)
+	}
+
+	_, body = listUsers(t, router, "name=JA&sort=-name")
+	if len(body.Data) != 3 || body.Data[0].Name != "Janet" || body.Data[2].Name != "Benjamin" || body.Pagination.Total != 3 {
+		t.Errorf("expected Janet, Jane, Benjamin, got %+v", body)
+	}
+
+	_, body = listUsers(t, router, "created_from=2024-01-02T00:00:00Z&created_to=2024-01-04T00:00:00Z")
+	if len(body.Data) != 2 || body.Data[0].Name != "Jane" || body.Data[1].Name != "Janet" {
+		t.Errorf("expected users created on days 2 and 3, got %+v", body)
+	}
+
+	_, body = listUsers(t, router, "email=jane@example.com")
+	if len(body.Data) != 1 || body.Data[0].Name != "Jane" {
+		t.Errorf("expected Jane only, got %+v", body)
+	}
+}
+
+func TestListUsersValidatesQuery(t *testing.T) {
+	router := newListRouter(t)
+
+	status, body := listUsers(t, router, "page=-1&page_size=500&sort=password&created_from=2024-02-01T00:00:00Z&created_to=2024-01-01T00:00:00Z")
+	if status != http.StatusUnprocessableEntity {
+		t.Fatalf("expected status 422, got %d", status)
+	}
+
+	fields := map[string]bool{}
+	for _, e := range body.Details {
+		fields[e.Field] = true
+	}
+	for _, field := range []string{"page", "page_size", "sort", "created_to"} {
+		if !fields[field] {
+			t.Errorf("expected an error on %s, got %+v", field, body.Details)
+		}
+	}
+
+	if status, _ := listUsers(t, router, "created_from=yesterday"); status != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a malformed time, got %d", status)
+	}
+}
@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := utils.ExponentialBackoff(time.Second, 10*time.Second)
+
+	tests := []struct {
+		attempt int
+		min     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{10, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		delay := backoff(tt.attempt)
+		if delay < tt.min || delay > tt.min+tt.min/5 {
+			t.Errorf("attempt %d: delay %v outside [%v, %v]", tt.attempt, delay, tt.min, tt.min+tt.min/5)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package tests
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -238,3 +239,101 @@ func TestValidationError(t *testing.T) {
 		t.Errorf("Error(): got %s, want %s", err.Error(), expected)
 	}
 }
+
+func TestErrorChains(t *testing.T) {
+	sentinel := errors.New("record not found")
+
+	t.Run("unwrap app error", func(t *testing.T) {
+		err := fmt.Errorf("get user: %w", utils.NewAppErrorWithCause(utils.CodeTimeout, "slow", sentinel))
+		if !errors.Is(err, sentinel) {
+			t.Error("Expected errors.Is to see the cause")
+		}
+		if !errors.Is(err, utils.NewAppError(utils.CodeTimeout, "")) {
+			t.Error("Expected errors.Is to match by code")
+		}
+		if errors.Is(err, utils.NewAppError(utils.CodeDeadlock, "")) {
+			t.Error("Expected a different code not to match")
+		}
+	})
+
+	t.Run("unwrap internal server error", func(t *testing.T) {
+		err := utils.NewInternalServerError("failed", sentinel)
+		if !errors.Is(err, sentinel) {
+			t.Error("Expected errors.Is to see the cause")
+		}
+	})
+
+	t.Run("value errors match pointer targets", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", utils.NewNotFoundError("user", 7))
+		var notFound *utils.NotFoundError
+		if !errors.As(err, &notFound) || notFound.ID != 7 {
+			t.Errorf("Expected *NotFoundError, got %v", notFound)
+		}
+
+		var conflict *utils.ConflictError
+		if !errors.As(utils.NewFieldConflictError("email", "taken"), &conflict) || conflict.Field != "email" {
+			t.Errorf("Expected *ConflictError, got %v", conflict)
+		}
+	})
+
+	t.Run("pointer errors match value targets", func(t *testing.T) {
+		err := fmt.Errorf("wrapped: %w", &utils.ForbiddenError{Message: "admins only"})
+		var forbidden utils.ForbiddenError
+		if !errors.As(err, &forbidden) || forbidden.Message != "admins only" {
+			t.Errorf("Expected ForbiddenError, got %v", forbidden)
+		}
+
+		var verrs utils.ValidationErrors
+		if !errors.As(utils.NewValidationErrors().Add("name", "is required"), &verrs) || len(verrs.Errors) != 1 {
+			t.Errorf("Expected ValidationErrors, got %v", verrs)
+		}
+	})
+}
+
+func TestErrorStackFormatting(t *testing.T) {
+	utils.SetStackCapture(true)
+	defer utils.SetStackCapture(false)
+
+	cause := utils.NewAppError("DB_ERROR", "connection reset")
+	err := utils.NewAppErrorWithCause("SAVE_FAILED", "could not save", cause)
+
+	if len(err.Stack) == 0 {
+		t.Fatal("Expected a captured stack")
+	}
+	if fn := err.Stack.Frames()[0].Function; !strings.HasSuffix(fn, "TestErrorStackFormatting") {
+		t.Errorf("Expected the stack to start at the caller, got %s", fn)
+	}
+
+	verbose := fmt.Sprintf("%+v", err)
+	if !strings.Contains(verbose, "caused by: DB_ERROR: connection reset") {
+		t.Errorf("Expected the cause chain, got %s", verbose)
+	}
+	if strings.Count(verbose, "utils_errors_test.go") < 2 {
+		t.Errorf("Expected both stacks, got %s", verbose)
+	}
+
+	if plain := fmt.Sprintf("%v", err); plain != err.Error() {
+		t.Errorf("Expected %%v to print Error(), got %s", plain)
+	}
+
+	utils.SetStackCapture(false)
+	if len(utils.NewAppError("CODE", "message").Stack) != 0 {
+		t.Error("Expected no stack when capture is disabled")
+	}
+}
+
+func TestErrorCodeCatalog(t *testing.T) {
+	code, ok := utils.LookupErrorCode(utils.CodeNotFound)
+	if !ok || code.Status != 404 || code.Message == "" {
+		t.Errorf("Expected NOT_FOUND in the catalog, got %+v", code)
+	}
+
+	utils.RegisterErrorCode(utils.ErrorCode{Code: "PAYMENT_REQUIRED", Status: 402, Message: "payment required"})
+	if code, ok := utils.LookupErrorCode("PAYMENT_REQUIRED"); !ok || code.Status != 402 {
+		t.Errorf("Expected registered code, got %+v", code)
+	}
+
+	if _, ok := utils.LookupErrorCode("NOT_A_CODE"); ok {
+		t.Error("Expected unknown code to be missing")
+	}
+}
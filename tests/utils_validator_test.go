@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/miladev95/golang-project-structure/pkg/utils"
+)
+
+type validatorAddress struct {
+	City string `json:"city" validate:"required"`
+	Zip  string `json:"zip" validate:"min=5,max=5"`
+}
+
+type validatorItem struct {
+	SKU   string `json:"sku" validate:"required,uuid"`
+	Email string `json:"email" validate:"email"`
+}
+
+type validatorOrder struct {
+	Name     string            `json:"name" validate:"required,min=3,max=10"`
+	Status   string            `json:"status" validate:"oneof=draft|paid"`
+	Quantity int               `json:"quantity" validate:"max=5"`
+	Website  string            `json:"website" validate:"url"`
+	Phone    string            `json:"phone" validate:"phone"`
+	Handle   string            `json:"handle" validate:"username"`
+	Secret   string            `json:"secret" validate:"password"`
+	Address  *validatorAddress `json:"address" validate:"required"`
+	Items    []validatorItem   `json:"items" validate:"max=3"`
+	Ignored  validatorAddress  `json:"ignored" validate:"-"`
+	internal string            `validate:"required"`
+}
+
+// validationFields returns the field paths and messages in err
+func validationFields(t *testing.T, err error) map[string]string {
+	t.Helper()
+	var verrs *utils.ValidationErrors
+	if !errors.As(err, &verrs) {
+		t.Fatalf("expected ValidationErrors, got %v", err)
+	}
+	fields := map[string]string{}
+	for _, e := range verrs.Errors {
+		fields[e.Field] = e.Message
+	}
+	return fields
+}
+
+func TestValidateStructValid(t *testing.T) {
+	order := validatorOrder{
+		Name:     "order",
+		Status:   "paid",
+		Quantity: 2,
+		Website:  "https://example.com",
+		Phone:    "+1 555 123 4567",
+		Handle:   "jane_doe",
+		Secret:   "Secret123",
+		Address:  &validatorAddress{City: "Berlin", Zip: "10115"},
+		Items:    []validatorItem{{SKU: "123e4567-e89b-42d3-a456-426614174000"}},
+	}
+	if err := utils.ValidateStruct(&order); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+}
+
+func TestValidateStructReportsJSONPaths(t *testing.T) {
+	order := validatorOrder{
+		Name:     "ab",
+		Status:   "shipped",
+		Quantity: 9,
+		Website:  "ftp://example.com",
+		Phone:    "12",
+		Handle:   "a",
+		Secret:   "weak",
+		Address:  &validatorAddress{Zip: "123"},
+		Items: []validatorItem{
+			{SKU: "123e4567-e89b-42d3-a456-426614174000"},
+			{SKU: "123e4567-e89b-42d3-a456-426614174000"},
+			{SKU: "not-a-uuid", Email: "nope"},
+		},
+	}
+
+	fields := validationFields(t, utils.ValidateStruct(order))
+
+	expected := map[string]string{
+		"name":           "must be at least 3 characters",
+		"status":         "must be one of draft, paid",
+		"quantity":       "must be at most 5",
+		"website":        "must be a valid URL",
+		"phone":          "must be a valid phone number",
+		"handle":         "must be 3-20 letters, digits, underscores or hyphens",
+		"secret":         "must be at least 8 characters with an uppercase letter, a lowercase letter and a digit",
+		"address.city":   "is required",
+		"address.zip":    "must be at least 5 characters",
+		"items[2].sku":   "must be a valid UUID",
+		"items[2].email": "must be a valid email address",
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected %v, got %v", expected, fields)
+	}
+}
+
+func TestValidateStructRequiredAndOptional(t *testing.T) {
+	fields := validationFields(t, utils.ValidateStruct(validatorOrder{Name: "   "}))
+
+	expected := map[string]string{
+		"name":    "is required",
+		"address": "is required",
+	}
+	if !reflect.DeepEqual(fields, expected) {
+		t.Errorf("expected only required fields, got %v", fields)
+	}
+}
+
+func TestValidateStructCollectionSize(t *testing.T) {
+	order := validatorOrder{
+		Name:    "order",
+		Address: &validatorAddress{City: "Berlin"},
+		Items:   make([]validatorItem, 4),
+	}
+	for i := range order.Items {
+		order.Items[i].SKU = "123e4567-e89b-42d3-a456-426614174000"
+	}
+
+	fields := validationFields(t, utils.ValidateStruct(order))
+	if fields["items"] != "must be at most 3 items" || len(fields) != 1 {
+		t.Errorf("expected items size error only, got %v", fields)
+	}
+}
+
+func TestValidateStructSliceOfStructs(t *testing.T) {
+	items := []validatorItem{{SKU: "123e4567-e89b-42d3-a456-426614174000"}, {}}
+
+	fields := validationFields(t, utils.ValidateStruct(items))
+	if fields["[1].sku"] != "is required" || len(fields) != 1 {
+		t.Errorf("expected [1].sku error, got %v", fields)
+	}
+}
+
+func TestValidatorCustomRule(t *testing.T) {
+	v := utils.NewValidator()
+	v.Register("lowercase", func(value reflect.Value, _ string) error {
+		if value.String() != strings.ToLower(value.String()) {
+			return errors.New("must be lowercase")
+		}
+		return nil
+	})
+
+	type request struct {
+		Slug string `json:"slug" validate:"required,lowercase"`
+	}
+
+	if err := v.Validate(request{Slug: "ok"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	fields := validationFields(t, v.Validate(request{Slug: "NotOK"}))
+	if fields["slug"] != "must be lowercase" {
+		t.Errorf("expected custom rule message, got %v", fields)
+	}
+
+	// The default validator does not know the rule
+	err := utils.ValidateStruct(request{Slug: "ok"})
+	var verrs *utils.ValidationErrors
+	if err == nil || errors.As(err, &verrs) {
+		t.Errorf("expected unknown rule error, got %v", err)
+	}
+}